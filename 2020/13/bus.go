@@ -1,5 +1,7 @@
 // Copyright (C) 2020 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2020 - Day 12, Puzzle 1
 // Ship navigation.
 
@@ -0,0 +1,69 @@
+// Copyright (C) 2020 Matt Brown
+
+// Advent of Code 2020 - Day 13.
+// Bus schedules.
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run bus2.go < input). This package reads the schedule and
+// turns the bus contest into congruences for the Chinese Remainder Theorem.
+package day13
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mattbnz/aoc/lib/numtheory"
+)
+
+// Reads the earliest departure and the buses, where -1 is an "x".
+func ReadSchedule(r io.Reader) (depart int, buses []int, err error) {
+	s := bufio.NewScanner(r)
+	if !s.Scan() {
+		return 0, nil, fmt.Errorf("couldn't read departure")
+	}
+	if depart, err = strconv.Atoi(strings.TrimSpace(s.Text())); err != nil {
+		return 0, nil, fmt.Errorf("bad departure: %v", err)
+	}
+	if !s.Scan() {
+		return 0, nil, fmt.Errorf("couldn't read schedule")
+	}
+	for _, bus := range strings.Split(strings.TrimSpace(s.Text()), ",") {
+		if bus == "x" {
+			buses = append(buses, -1)
+			continue
+		}
+		b, err := strconv.Atoi(bus)
+		if err != nil || b <= 0 {
+			return 0, nil, fmt.Errorf("bad bus %q", bus)
+		}
+		buses = append(buses, b)
+	}
+	return depart, buses, nil
+}
+
+// The contest wants each bus to leave its position in the list after t:
+// t + i = 0 (mod bus), i.e. t = -i (mod bus).
+func Congruences(buses []int) []numtheory.Congruence {
+	cs := []numtheory.Congruence{}
+	for i, bus := range buses {
+		if bus == -1 {
+			continue
+		}
+		cs = append(cs, numtheory.Congruence{R: -i, M: bus})
+	}
+	return cs
+}
+
+// Returns the earliest timestamp at which the buses depart one after
+// another, as their positions in the schedule say. Bus numbers needn't be
+// coprime, but if they share factors there may be no such time.
+func Contest(buses []int) (int, error) {
+	c, err := numtheory.CRT(Congruences(buses)...)
+	if err != nil {
+		return 0, err
+	}
+	return c.R, nil
+}
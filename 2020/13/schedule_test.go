@@ -0,0 +1,75 @@
+package day13
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mattbnz/aoc/lib/numtheory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contest(t *testing.T, filename string) int {
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	_, buses, err := ReadSchedule(f)
+	require.NoError(t, err)
+	v, err := Contest(buses)
+	require.NoError(t, err)
+	return v
+}
+
+func Test_Contest(t *testing.T) {
+	assert.Equal(t, 1068781, contest(t, "sample"))
+	assert.Equal(t, 210612924879242, contest(t, "input"))
+
+	// The smaller examples from the puzzle.
+	for schedule, want := range map[string]int{
+		"17,x,13,19":      3417,
+		"67,7,59,61":      754018,
+		"67,x,7,59,61":    779210,
+		"67,7,x,59,61":    1261476,
+		"1789,37,47,1889": 1202161486,
+	} {
+		_, buses, err := ReadSchedule(strings.NewReader("0\n" + schedule))
+		require.NoError(t, err)
+		v, err := Contest(buses)
+		require.NoError(t, err)
+		assert.Equal(t, want, v, schedule)
+	}
+}
+
+// Buses sharing factors work when they can agree, and are reported when
+// they can't.
+func Test_NotCoprime(t *testing.T) {
+	v, err := Contest([]int{4, 6})
+	assert.True(t, errors.Is(err, numtheory.ErrNoSolution), err)
+
+	v, err = Contest([]int{4, -1, 6})
+	require.NoError(t, err)
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 0, (v+2)%6)
+
+	v, err = Contest([]int{11, -1, -1, 21, -1, -1, 15})
+	require.NoError(t, err)
+	for i, bus := range []int{11, -1, -1, 21, -1, -1, 15} {
+		if bus != -1 {
+			assert.Equal(t, 0, (v+i)%bus, bus)
+		}
+	}
+}
+
+func Test_ReadSchedule(t *testing.T) {
+	depart, buses, err := ReadSchedule(strings.NewReader("939\n7,13,x,x,59,x,31,19\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 939, depart)
+	assert.Equal(t, []int{7, 13, -1, -1, 59, -1, 31, 19}, buses)
+
+	for _, bad := range []string{"", "x\n7", "939", "939\n7,y", "939\n0,7"} {
+		_, _, err := ReadSchedule(strings.NewReader(bad))
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,255 @@
+// Advent of Code 2020 - Day 18.
+// Math operator precedence.
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run math.go < input). This package holds the expression
+// engine they share: a tokenizer and a Pratt parser building an AST, where
+// the operators, their precedence and associativity come from a Grammar.
+package day18
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type TokenKind int
+
+const (
+	NUMBER TokenKind = iota
+	OP
+	LPAREN
+	RPAREN
+)
+
+type Token struct {
+	Kind TokenKind
+	Text string
+	Pos  int // offset in the input, for errors
+}
+
+func (t Token) String() string {
+	return fmt.Sprintf("%q at %d", t.Text, t.Pos)
+}
+
+// Splits s into numbers, brackets and (single character) operators.
+func Tokenize(s string) (rv []Token, err error) {
+	rs := []rune(s)
+	for n := 0; n < len(rs); n++ {
+		r := rs[n]
+		switch {
+		case unicode.IsSpace(r):
+		case unicode.IsDigit(r):
+			start := n
+			for n+1 < len(rs) && unicode.IsDigit(rs[n+1]) {
+				n++
+			}
+			rv = append(rv, Token{NUMBER, string(rs[start : n+1]), start})
+		case r == '(':
+			rv = append(rv, Token{LPAREN, "(", n})
+		case r == ')':
+			rv = append(rv, Token{RPAREN, ")", n})
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			rv = append(rv, Token{OP, string(r), n})
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", r, n)
+		}
+	}
+	return
+}
+
+type Assoc int
+
+const (
+	LEFT Assoc = iota
+	RIGHT
+)
+
+// Operator describes how a binary operator binds, and what it does. Higher
+// Prec binds tighter.
+type Operator struct {
+	Prec  int
+	Assoc Assoc
+	Apply func(a, b int) (int, error)
+}
+
+// Grammar maps each operator symbol to how it behaves.
+type Grammar map[string]Operator
+
+func add(a, b int) (int, error) { return a + b, nil }
+func sub(a, b int) (int, error) { return a - b, nil }
+func mul(a, b int) (int, error) { return a * b, nil }
+
+func div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return a / b, nil
+}
+
+func pow(a, b int) (int, error) {
+	if b < 0 {
+		return 0, fmt.Errorf("negative exponent %d", b)
+	}
+	rv := 1
+	for ; b > 0; b-- {
+		rv *= a
+	}
+	return rv, nil
+}
+
+var (
+	// Part 1: + and * are equal, evaluated left to right.
+	LeftToRight = Grammar{
+		"+": {1, LEFT, add},
+		"*": {1, LEFT, mul},
+	}
+	// Part 2: + binds tighter than *.
+	AdditionFirst = Grammar{
+		"+": {2, LEFT, add},
+		"*": {1, LEFT, mul},
+	}
+	// The usual rules of arithmetic, with integer division.
+	Arithmetic = Grammar{
+		"+": {1, LEFT, add},
+		"-": {1, LEFT, sub},
+		"*": {2, LEFT, mul},
+		"/": {2, LEFT, div},
+		"^": {3, RIGHT, pow},
+	}
+)
+
+// Node is a node of the expression tree.
+type Node interface {
+	Eval() (int, error)
+	// Prints the expression with every operation bracketed.
+	String() string
+}
+
+type Num int
+
+func (n Num) Eval() (int, error) {
+	return int(n), nil
+}
+
+func (n Num) String() string {
+	return strconv.Itoa(int(n))
+}
+
+type BinOp struct {
+	Op   string
+	L, R Node
+
+	apply func(a, b int) (int, error)
+}
+
+func (b *BinOp) Eval() (int, error) {
+	l, err := b.L.Eval()
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.R.Eval()
+	if err != nil {
+		return 0, err
+	}
+	return b.apply(l, r)
+}
+
+func (b *BinOp) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.L, b.Op, b.R)
+}
+
+type parser struct {
+	g    Grammar
+	toks []Token
+	n    int
+}
+
+func (p *parser) next() (Token, bool) {
+	if p.n >= len(p.toks) {
+		return Token{}, false
+	}
+	p.n++
+	return p.toks[p.n-1], true
+}
+
+// Parses a number or a bracketed expression.
+func (p *parser) operand() (Node, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	switch t.Kind {
+	case NUMBER:
+		v, err := strconv.Atoi(t.Text)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %s: %v", t, err)
+		}
+		return Num(v), nil
+	case LPAREN:
+		e, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := p.next(); !ok || c.Kind != RPAREN {
+			return nil, fmt.Errorf("missing ) for ( at %d", t.Pos)
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("expected a number or (, got %s", t)
+}
+
+// Parses operands joined by operators of at least minPrec.
+func (p *parser) expr(minPrec int) (Node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.n < len(p.toks) && p.toks[p.n].Kind == OP {
+		t := p.toks[p.n]
+		op, found := p.g[t.Text]
+		if !found {
+			return nil, fmt.Errorf("unknown operator %s", t)
+		}
+		if op.Prec < minPrec {
+			break
+		}
+		p.n++
+		nextPrec := op.Prec + 1
+		if op.Assoc == RIGHT {
+			nextPrec = op.Prec
+		}
+		right, err := p.expr(nextPrec)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinOp{Op: t.Text, L: left, R: right, apply: op.Apply}
+	}
+	return left, nil
+}
+
+// Parses s into an expression tree using the grammar's operators.
+func (g Grammar) Parse(s string) (Node, error) {
+	toks, err := Tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{g: g, toks: toks}
+	e, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t, more := p.next(); more {
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+	return e, nil
+}
+
+// Parses and evaluates s.
+func (g Grammar) Eval(s string) (int, error) {
+	e, err := g.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return e.Eval()
+}
@@ -0,0 +1,100 @@
+package day18
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tokenize(t *testing.T) {
+	toks, err := Tokenize("12 *(3+ 45)")
+	require.NoError(t, err)
+	assert.Equal(t, []Token{
+		{NUMBER, "12", 0}, {OP, "*", 3}, {LPAREN, "(", 4}, {NUMBER, "3", 5},
+		{OP, "+", 6}, {NUMBER, "45", 8}, {RPAREN, ")", 10},
+	}, toks)
+
+	_, err = Tokenize("1 + x")
+	assert.Error(t, err)
+}
+
+// The examples from the puzzle, with both parts' answers.
+var examples = []struct {
+	expr         string
+	part1, part2 int
+}{
+	{"1 + 2 * 3 + 4 * 5 + 6", 71, 231},
+	{"1 + (2 * 3) + (4 * (5 + 6))", 51, 51},
+	{"2 * 3 + (4 * 5)", 26, 46},
+	{"5 + (8 * 3 + 9 + 3 * 4 * 3)", 437, 1445},
+	{"5 * 9 * (7 * 3 * 3 + 9 * 3 + (8 + 6 * 4))", 12240, 669060},
+	{"((2 + 4 * 9) * (6 + 9 * 8 + 6) + 6) + 2 + 4 * 2", 13632, 23340},
+}
+
+func Test_Sample(t *testing.T) {
+	for _, e := range examples {
+		v, err := LeftToRight.Eval(e.expr)
+		require.NoError(t, err)
+		assert.Equal(t, e.part1, v, e.expr)
+
+		v, err = AdditionFirst.Eval(e.expr)
+		require.NoError(t, err)
+		assert.Equal(t, e.part2, v, e.expr)
+	}
+}
+
+func Test_String(t *testing.T) {
+	for _, tc := range []struct {
+		g          Grammar
+		expr, want string
+	}{
+		{LeftToRight, "1 + 2 * 3 + 4", "(((1 + 2) * 3) + 4)"},
+		{AdditionFirst, "1 + 2 * 3 + 4", "((1 + 2) * (3 + 4))"},
+		{Arithmetic, "1 + 2 * 3 + 4", "((1 + (2 * 3)) + 4)"},
+		{Arithmetic, "2 ^ 3 ^ 2", "(2 ^ (3 ^ 2))"},
+		{Arithmetic, "8 - 3 - 2", "((8 - 3) - 2)"},
+		{Arithmetic, "((7))", "7"},
+	} {
+		e, err := tc.g.Parse(tc.expr)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, e.String(), tc.expr)
+
+		// Printed trees parse back to themselves.
+		again, err := tc.g.Parse(e.String())
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, again.String())
+	}
+}
+
+func Test_Arithmetic(t *testing.T) {
+	for expr, want := range map[string]int{
+		"1 + 2 * 3":     7,
+		"(1 + 2) * 3":   9,
+		"2 ^ 3 ^ 2":     512,
+		"100 / 10 / 5":  2,
+		"10 - 4 - 3":    3,
+		"2 * 3 ^ 2 - 1": 17,
+	} {
+		v, err := Arithmetic.Eval(expr)
+		require.NoError(t, err)
+		assert.Equal(t, want, v, expr)
+	}
+}
+
+func Test_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 + 2)",
+		"1 2",
+		"1 - 2", // not a part 1 operator
+		"* 3",
+	} {
+		_, err := LeftToRight.Eval(expr)
+		assert.Error(t, err, expr)
+	}
+	_, err := Arithmetic.Eval("1 / (2 - 2)")
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2020 Matt Brown
+
+//go:build ignore
+
+// Advent of Code 2020 - Day 19, Puzzle 2
+// Monster Message matching, with loops in the rules.
+//
+// Run with -v to see how each matching message matched.
+
+package main
+
+import (
+    "bufio"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+
+    "day19"
+)
+
+var verbose = flag.Bool("v", false, "print the rule tree of each match")
+
+func main() {
+    flag.Parse()
+    s := bufio.NewScanner(os.Stdin)
+
+    // Read in rules, and replace 8 and 11 with their looping versions
+    rules, err := day19.ReadRules(s)
+    if err != nil {
+        log.Fatal(err)
+    }
+    if err := rules.Override("8: 42 | 42 8", "11: 42 31 | 42 11 31"); err != nil {
+        log.Fatal(err)
+    }
+
+    // Match Strings to rules
+    sum := 0
+    for s.Scan() {
+        if !rules.Match(0, s.Text()) {
+            continue
+        }
+        sum++
+        if *verbose {
+            t, _ := rules.Tree(0, s.Text())
+            fmt.Println(t)
+        }
+    }
+
+    fmt.Println(sum)
+}
@@ -0,0 +1,272 @@
+// Advent of Code 2020 - Day 19.
+// Monster Message matching.
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run messages.go < input). This package holds the rule
+// matcher they share, which finds every way a rule can match so that
+// recursive rules (such as part 2's replacements) work too.
+package day19
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A rule either matches a literal string, or any one of its options, each
+// a sequence of other rules.
+type Rule struct {
+	rules [][]int
+	match string
+}
+
+func (r Rule) String() string {
+	if len(r.rules) == 0 {
+		return strconv.Quote(r.match)
+	}
+	opts := []string{}
+	for _, o := range r.rules {
+		s := []string{}
+		for _, n := range o {
+			s = append(s, strconv.Itoa(n))
+		}
+		opts = append(opts, strings.Join(s, " "))
+	}
+	return strings.Join(opts, " | ")
+}
+
+// Parses a rule line, e.g. `1: 2 3 | 3 2` or `4: "a"`.
+func ParseRule(line string) (int, Rule, error) {
+	t := strings.Split(line, ": ")
+	if len(t) != 2 || t[1] == "" {
+		return 0, Rule{}, fmt.Errorf("bad rule: %s", line)
+	}
+	n, err := strconv.Atoi(t[0])
+	if err != nil {
+		return 0, Rule{}, fmt.Errorf("bad rule number: %s", line)
+	}
+	r := Rule{}
+	if t[1][0] == '"' {
+		r.match = strings.Trim(t[1], "\"")
+		if r.match == "" {
+			return 0, Rule{}, fmt.Errorf("empty match: %s", line)
+		}
+		return n, r, nil
+	}
+	for _, m := range strings.Split(t[1], " | ") {
+		l := []int{}
+		for _, p := range strings.Fields(m) {
+			v, err := strconv.Atoi(p)
+			if err != nil {
+				return 0, Rule{}, fmt.Errorf("bad rule reference %q: %s", p, line)
+			}
+			l = append(l, v)
+		}
+		if len(l) == 0 {
+			return 0, Rule{}, fmt.Errorf("empty option: %s", line)
+		}
+		r.rules = append(r.rules, l)
+	}
+	return n, r, nil
+}
+
+// Rules are the puzzle's rules, by number.
+type Rules map[int]Rule
+
+// Reads rules from s up to the first blank line.
+func ReadRules(s *bufio.Scanner) (Rules, error) {
+	rules := Rules{}
+	for s.Scan() && s.Text() != "" {
+		n, r, err := ParseRule(s.Text())
+		if err != nil {
+			return nil, err
+		}
+		rules[n] = r
+	}
+	return rules, rules.Check()
+}
+
+// Checks every rule referred to exists.
+func (rules Rules) Check() error {
+	for n, r := range rules {
+		for _, o := range r.rules {
+			for _, rn := range o {
+				if _, found := rules[rn]; !found {
+					return fmt.Errorf("rule %d refers to missing rule %d", n, rn)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Replaces (or adds) rules, given as rule lines, e.g. part 2's
+// `8: 42 | 42 8`.
+func (rules Rules) Override(lines ...string) error {
+	for _, l := range lines {
+		n, r, err := ParseRule(l)
+		if err != nil {
+			return err
+		}
+		rules[n] = r
+	}
+	return rules.Check()
+}
+
+type matcher struct {
+	rules   Rules
+	message string
+	memo    map[[2]int][]int
+}
+
+// Returns every position in the message that rule can match up to, starting
+// from at. A rule that (directly or not) refers back to itself without
+// consuming anything can't match that way, so left recursion ends rather
+// than looping.
+func (m *matcher) ends(rule, at int) []int {
+	key := [2]int{rule, at}
+	if rv, found := m.memo[key]; found {
+		return rv
+	}
+	m.memo[key] = nil
+	r, found := m.rules[rule]
+	if !found {
+		return nil
+	}
+	rv := []int{}
+	if len(r.rules) == 0 {
+		if strings.HasPrefix(m.message[at:], r.match) {
+			rv = append(rv, at+len(r.match))
+		}
+		m.memo[key] = rv
+		return rv
+	}
+	seen := map[int]bool{}
+	for _, option := range r.rules {
+		for _, end := range m.seq(option, at) {
+			if !seen[end] {
+				seen[end] = true
+				rv = append(rv, end)
+			}
+		}
+	}
+	sort.Ints(rv)
+	m.memo[key] = rv
+	return rv
+}
+
+// Returns every position the sequence of rules can match up to from at.
+func (m *matcher) seq(option []int, at int) []int {
+	pos := []int{at}
+	for _, rn := range option {
+		seen := map[int]bool{}
+		next := []int{}
+		for _, p := range pos {
+			for _, end := range m.ends(rn, p) {
+				if !seen[end] {
+					seen[end] = true
+					next = append(next, end)
+				}
+			}
+		}
+		pos = next
+	}
+	return pos
+}
+
+func (rules Rules) matcher(message string) *matcher {
+	return &matcher{rules: rules, message: message, memo: map[[2]int][]int{}}
+}
+
+// Returns every length of the start of message that rule can match.
+func (rules Rules) Lengths(rule int, message string) []int {
+	return rules.matcher(message).ends(rule, 0)
+}
+
+// Reports whether rule matches the whole of message.
+func (rules Rules) Match(rule int, message string) bool {
+	for _, l := range rules.Lengths(rule, message) {
+		if l == len(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tree shows how a rule matched part of a message.
+type Tree struct {
+	Rule     int
+	Text     string
+	Children []*Tree
+}
+
+// Prints the tree, one rule per line, indented under the rule using it.
+func (t *Tree) String() string {
+	var b strings.Builder
+	var walk func(t *Tree, depth int)
+	walk = func(t *Tree, depth int) {
+		fmt.Fprintf(&b, "%s%d: %s\n", strings.Repeat("  ", depth), t.Rule, t.Text)
+		for _, c := range t.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(t, 0)
+	return b.String()
+}
+
+// Returns how rule matches the whole of message, or false if it doesn't.
+// Where there are several ways to match, the first option tried wins.
+func (rules Rules) Tree(rule int, message string) (*Tree, bool) {
+	m := rules.matcher(message)
+	return m.tree(rule, 0, len(message))
+}
+
+// Returns the tree for rule matching exactly message[at:end].
+func (m *matcher) tree(rule, at, end int) (*Tree, bool) {
+	if !contains(m.ends(rule, at), end) {
+		return nil, false
+	}
+	t := &Tree{Rule: rule, Text: m.message[at:end]}
+	r := m.rules[rule]
+	if len(r.rules) == 0 {
+		return t, true
+	}
+	for _, option := range r.rules {
+		if children, ok := m.split(option, at, end); ok {
+			t.Children = children
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Returns trees for the sequence of rules matching exactly message[at:end].
+func (m *matcher) split(option []int, at, end int) ([]*Tree, bool) {
+	if len(option) == 0 {
+		return nil, at == end
+	}
+	for _, mid := range m.ends(option[0], at) {
+		if !contains(m.seq(option[1:], mid), end) {
+			continue
+		}
+		first, ok := m.tree(option[0], at, mid)
+		if !ok {
+			continue
+		}
+		if rest, ok := m.split(option[1:], mid, end); ok {
+			return append([]*Tree{first}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+func contains(l []int, v int) bool {
+	for _, x := range l {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package day19
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reads the rules and messages from filename.
+func load(t *testing.T, filename string) (Rules, []string) {
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	rules, err := ReadRules(s)
+	require.NoError(t, err)
+	messages := []string{}
+	for s.Scan() {
+		messages = append(messages, s.Text())
+	}
+	return rules, messages
+}
+
+func count(rules Rules, messages []string) (rv int) {
+	for _, m := range messages {
+		if rules.Match(0, m) {
+			rv++
+		}
+	}
+	return
+}
+
+func Test_Sample(t *testing.T) {
+	rules, messages := load(t, "sample")
+	assert.Equal(t, 6, len(rules))
+	assert.Equal(t, "2 3 | 3 2", rules[1].String())
+	assert.Equal(t, `"a"`, rules[4].String())
+
+	assert.Equal(t, 2, count(rules, messages))
+	assert.True(t, rules.Match(0, "ababbb"))
+	assert.False(t, rules.Match(0, "bababa"))
+	assert.False(t, rules.Match(0, "aaaabbb"), "only a prefix matches")
+	assert.Equal(t, []int{6}, rules.Lengths(0, "aaaabbb"))
+}
+
+func Test_Sample_Part2(t *testing.T) {
+	rules, messages := load(t, "sample2")
+	assert.Equal(t, 3, count(rules, messages))
+
+	require.NoError(t, rules.Override("8: 42 | 42 8", "11: 42 31 | 42 11 31"))
+	assert.Equal(t, "42 | 42 8", rules[8].String())
+	assert.Equal(t, 12, count(rules, messages))
+	assert.True(t, rules.Match(0, "babbbbaabbbbbabbbbbbaabaaabaaa"))
+	assert.False(t, rules.Match(0, "aaaabbaaaabbaaa"))
+}
+
+func Test_Lengths(t *testing.T) {
+	rules := Rules{}
+	require.NoError(t, rules.Override(`1: "a"`, "0: 1 | 1 0"))
+	assert.Equal(t, []int{1, 2, 3}, rules.Lengths(0, "aaab"))
+
+	// Left recursion can't consume anything more, but doesn't loop either.
+	require.NoError(t, rules.Override("0: 1 | 0 1"))
+	assert.Equal(t, []int{1}, rules.Lengths(0, "aaab"))
+}
+
+func Test_Tree(t *testing.T) {
+	rules, _ := load(t, "sample")
+	tree, ok := rules.Tree(0, "ababbb")
+	require.True(t, ok)
+	assert.Equal(t, strings.Join([]string{
+		"0: ababbb",
+		"  4: a",
+		"  1: babb",
+		"    3: ba",
+		"      5: b",
+		"      4: a",
+		"    2: bb",
+		"      5: b",
+		"      5: b",
+		"  5: b",
+		"",
+	}, "\n"), tree.String())
+
+	_, ok = rules.Tree(0, "bababa")
+	assert.False(t, ok)
+}
+
+func Test_Errors(t *testing.T) {
+	for _, l := range []string{"1 2 3", "x: 1", "1: ", `1: ""`, "1: 2 | x", "1: 2 | "} {
+		_, _, err := ParseRule(l)
+		assert.Error(t, err, l)
+	}
+	rules := Rules{}
+	assert.Error(t, rules.Override("0: 1 2"))
+	assert.False(t, rules.Match(3, "a"))
+}
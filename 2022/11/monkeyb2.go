@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 11, Puzzle 1.
 // Monkey in the Middle - Monkey Business!
 //
@@ -0,0 +1,231 @@
+// Copyright (C) 2022 Matt Brown
+
+// Advent of Code 2022 - Day 11.
+// Monkey in the Middle - Monkey Business!
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run monkeyb3.go < input). This package reads the monkeys and
+// plays their rounds, either checking every worry level for overflow or,
+// when there is no relief, keeping them modulo the lcm of the divisors.
+package day11
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattbnz/aoc/lib/numtheory"
+)
+
+type Monkey struct {
+	Queue     []int
+	MultBy    int // -1 for old
+	AddBy     int // -1 for old
+	Divisor   int
+	DestTrue  int
+	DestFalse int
+
+	Inspections int
+}
+
+func ParseQueue(l string) ([]int, error) {
+	rv := []int{}
+	_, items, _ := strings.Cut(l, ":")
+	if strings.TrimSpace(items) == "" {
+		return rv, nil
+	}
+	for _, i := range strings.Split(items, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(i))
+		if err != nil {
+			return nil, fmt.Errorf("bad items (%s): %v", l, err)
+		}
+		rv = append(rv, v)
+	}
+	return rv, nil
+}
+
+var lastnumRE = regexp.MustCompile(`(\d+)$`)
+var opRE = regexp.MustCompile(`new = old ([*+]) (old|\d+)$`)
+
+func LastNumber(l string) (int, error) {
+	m := lastnumRE.FindStringSubmatch(l)
+	if m == nil {
+		return 0, fmt.Errorf("no last number in %s", l)
+	}
+	return strconv.Atoi(m[0])
+}
+
+// Returns what the operation multiplies or adds by.
+func ParseOp(l string) (mult, add int, err error) {
+	m := opRE.FindStringSubmatch(l)
+	if m == nil {
+		return 0, 0, fmt.Errorf("couldn't parse operation %s", l)
+	}
+	v := -1
+	if m[2] != "old" {
+		if v, err = strconv.Atoi(m[2]); err != nil {
+			return 0, 0, err
+		}
+	}
+	if m[1] == "*" {
+		return v, 0, nil
+	}
+	return 0, v, nil
+}
+
+func ValFor(v int) string {
+	if v == -1 {
+		return "old"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+func (m *Monkey) Op() string {
+	if m.MultBy != 0 {
+		return fmt.Sprintf("* %s", ValFor(m.MultBy))
+	}
+	return fmt.Sprintf("+ %s", ValFor(m.AddBy))
+}
+
+func (m *Monkey) String() string {
+	s := []string{}
+	for _, i := range m.Queue {
+		s = append(s, fmt.Sprintf("%d", i))
+	}
+	return fmt.Sprintf("Starting items: %s\nOperation: new = old %s\nTest: divisible by %d\n  If true: throw to monkey %d\n  If false: throw to monkey %d\n",
+		strings.Join(s, ", "), m.Op(), m.Divisor, m.DestTrue, m.DestFalse)
+}
+
+func ReadMonkeys(r io.Reader) ([]*Monkey, error) {
+	s := bufio.NewScanner(r)
+	monkeys := []*Monkey{}
+	var err error
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" {
+			continue
+		}
+		if strings.HasPrefix(l, "Monkey ") {
+			monkeys = append(monkeys, &Monkey{})
+			continue
+		}
+		if len(monkeys) == 0 {
+			return nil, fmt.Errorf("%s before any monkey", l)
+		}
+		m := monkeys[len(monkeys)-1]
+		switch {
+		case strings.HasPrefix(l, "Starting"):
+			m.Queue, err = ParseQueue(l)
+		case strings.HasPrefix(l, "Operation"):
+			m.MultBy, m.AddBy, err = ParseOp(l)
+		case strings.HasPrefix(l, "Test"):
+			m.Divisor, err = LastNumber(l)
+		case strings.HasPrefix(l, "If true"):
+			m.DestTrue, err = LastNumber(l)
+		case strings.HasPrefix(l, "If false"):
+			m.DestFalse, err = LastNumber(l)
+		default:
+			err = fmt.Errorf("unknown line %s", l)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for n, m := range monkeys {
+		if m.Divisor <= 0 {
+			return nil, fmt.Errorf("monkey %d has no divisor", n)
+		}
+		if m.DestTrue >= len(monkeys) || m.DestFalse >= len(monkeys) {
+			return nil, fmt.Errorf("monkey %d throws to a missing monkey", n)
+		}
+	}
+	return monkeys, s.Err()
+}
+
+// Troop is the monkeys playing keep away with our items.
+type Troop struct {
+	Monkeys []*Monkey
+	// Worry levels are divided by this after each inspection.
+	Relief int
+	// With no relief, worry levels are kept modulo this, the lcm of the
+	// divisors, which doesn't change where any item is thrown.
+	Mod int
+}
+
+func NewTroop(monkeys []*Monkey, relief int) (*Troop, error) {
+	t := &Troop{Monkeys: monkeys, Relief: relief}
+	if relief == 1 {
+		divisors := []int{}
+		for _, m := range monkeys {
+			divisors = append(divisors, m.Divisor)
+		}
+		mod, err := numtheory.LCM(divisors...)
+		if err != nil {
+			return nil, err
+		}
+		t.Mod = mod
+	}
+	return t, nil
+}
+
+// Returns the worry level after m inspects an item.
+func (t *Troop) inspect(m *Monkey, w int) (int, error) {
+	by := m.MultBy
+	if m.MultBy == 0 {
+		by = m.AddBy
+	}
+	if by == -1 {
+		by = w
+	}
+	if t.Mod > 0 {
+		if m.MultBy != 0 {
+			return numtheory.MulMod(w, by, t.Mod), nil
+		}
+		return numtheory.AddMod(w, by, t.Mod), nil
+	}
+	v, ok := numtheory.Add(w, by)
+	if m.MultBy != 0 {
+		v, ok = numtheory.Mul(w, by)
+	}
+	if !ok {
+		return 0, fmt.Errorf("worry level %d %s: %w", w, m.Op(), numtheory.ErrOverflow)
+	}
+	return v / t.Relief, nil
+}
+
+// Each monkey in turn inspects and throws all its items.
+func (t *Troop) Round() error {
+	for _, m := range t.Monkeys {
+		for _, w := range m.Queue {
+			m.Inspections++
+			w, err := t.inspect(m, w)
+			if err != nil {
+				return err
+			}
+			next := m.DestFalse
+			if w%m.Divisor == 0 {
+				next = m.DestTrue
+			}
+			t.Monkeys[next].Queue = append(t.Monkeys[next].Queue, w)
+		}
+		m.Queue = []int{}
+	}
+	return nil
+}
+
+// The product of the two highest inspection counts.
+func (t *Troop) Business() int {
+	counts := []int{}
+	for _, m := range t.Monkeys {
+		counts = append(counts, m.Inspections)
+	}
+	sort.Ints(counts)
+	if len(counts) < 2 {
+		return 0
+	}
+	return counts[len(counts)-2] * counts[len(counts)-1]
+}
@@ -0,0 +1,114 @@
+package day11
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mattbnz/aoc/lib/numtheory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func troop(t *testing.T, filename string, relief int) *Troop {
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	monkeys, err := ReadMonkeys(f)
+	require.NoError(t, err)
+	troop, err := NewTroop(monkeys, relief)
+	require.NoError(t, err)
+	return troop
+}
+
+func play(t *testing.T, troop *Troop, rounds int) {
+	for i := 0; i < rounds; i++ {
+		require.NoError(t, troop.Round())
+	}
+}
+
+func Test_Sample(t *testing.T) {
+	tr := troop(t, "sample", 3)
+	require.Equal(t, 4, len(tr.Monkeys))
+	assert.Equal(t, "Starting items: 79, 98\nOperation: new = old * 19\nTest: divisible by 23\n  If true: throw to monkey 2\n  If false: throw to monkey 3\n", tr.Monkeys[0].String())
+	assert.Equal(t, "* old", tr.Monkeys[2].Op())
+	play(t, tr, 1)
+	assert.Equal(t, []int{20, 23, 27, 26}, tr.Monkeys[0].Queue)
+	assert.Equal(t, []int{2080, 25, 167, 207, 401, 1046}, tr.Monkeys[1].Queue)
+	play(t, tr, 19)
+	assert.Equal(t, 10605, tr.Business())
+
+	tr = troop(t, "sample", 1)
+	assert.Equal(t, 96577, tr.Mod)
+	play(t, tr, 20)
+	assert.Equal(t, []int{99, 97, 8, 103}, inspections(tr))
+	play(t, tr, 9980)
+	assert.Equal(t, 2713310158, tr.Business())
+}
+
+func inspections(tr *Troop) []int {
+	rv := []int{}
+	for _, m := range tr.Monkeys {
+		rv = append(rv, m.Inspections)
+	}
+	return rv
+}
+
+func Test_Input(t *testing.T) {
+	tr := troop(t, "input", 3)
+	play(t, tr, 20)
+	assert.Equal(t, 99840, tr.Business())
+
+	tr = troop(t, "input", 1)
+	play(t, tr, 10000)
+	assert.Equal(t, 20683044837, tr.Business())
+}
+
+// Divisors sharing factors only need their lcm, and without one the worry
+// levels overflow rather than silently wrapping.
+func Test_Worry(t *testing.T) {
+	monkeys, err := ReadMonkeys(strings.NewReader(`Monkey 0:
+  Starting items: 5
+  Operation: new = old * old
+  Test: divisible by 4
+    If true: throw to monkey 1
+    If false: throw to monkey 1
+
+Monkey 1:
+  Starting items:
+  Operation: new = old + 3
+  Test: divisible by 6
+    If true: throw to monkey 0
+    If false: throw to monkey 0
+`))
+	require.NoError(t, err)
+	tr, err := NewTroop(monkeys, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 12, tr.Mod)
+	play(t, tr, 10)
+	assert.Equal(t, []int{10, 10}, inspections(tr))
+
+	for _, m := range monkeys {
+		m.Queue = []int{5}
+	}
+	tr.Mod = 0
+	for err == nil {
+		err = tr.Round()
+	}
+	assert.True(t, errors.Is(err, numtheory.ErrOverflow), err)
+}
+
+func Test_ReadMonkeys(t *testing.T) {
+	for _, bad := range []string{
+		"  Starting items: 1",
+		"Monkey 0:\n  Operation: new = old - 3",
+		"Monkey 0:\n  Starting items: 1, x",
+		"Monkey 0:\n  Test: divisible by 3\n  If true: throw to monkey 1",
+		"Monkey 0:\n  Juggling",
+		"Monkey 0:\n",
+	} {
+		_, err := ReadMonkeys(strings.NewReader(bad))
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,7 @@
+// Advent of Code 2022 - Day 12.
+// Hill Climbing Algorithm.
+//
+// Each puzzle is a standalone program, built only when named explicitly:
+//
+//	go run hillclimb.go < input
+package day12
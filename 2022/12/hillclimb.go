@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 12, Puzzle 1.
 // Hill Climbing Algorithm.
 
@@ -11,6 +13,8 @@ import (
 	"log"
 	"os"
 	"strings"
+
+	"github.com/mattbnz/aoc/lib/search"
 )
 
 func Abs(x int) int {
@@ -49,8 +53,6 @@ func (p Pos) Down() Pos {
 type Cell struct {
 	id     Pos
 	height int
-
-	dist int // distance to this cell
 }
 
 var Reset = "\033[0m"
@@ -177,43 +179,12 @@ func (g *Grid) Debug(dist int, msg string, args ...any) {
 	fmt.Println("")
 }
 
-func (g *Grid) Explore(from Pos, to Pos, dist int) {
-	c := g.Cell(from)
-	g.Debug(dist, "%d: %s", dist, c)
-	o := g.MoveOptions(from)
-	explore := []*Cell{}
-	// Update cells first to prevent loops
-	for _, c := range o {
-		if c.dist == 0 {
-			// New cell, not seen before
-			c.dist = dist
-			g.Debug(dist, "=> %s @ %d", c, dist)
-			explore = append(explore, c)
-		} else if c.dist > dist {
-			// We found a better path to c, update
-			g.Debug(dist, "=> %s @ %d (was %d)", c, dist, c.dist)
-			c.dist = dist
-			explore = append(explore, c) // TODO: what about previous explore...
-		} else if c.dist < dist {
-			// that option is already being explored, and is shorter, so abandon this path entirely
-			//g.Debug(dist, "! %s (shorter @ %d vs %d)", c, c.dist, dist)
-			//return
-		} else {
-			// don't need to do anything for the == dist case (I think?)
-			//g.Debug(dist, ". %s (same @ %d vs %d)", c, c.dist, dist)
-		}
-		if c.id.DistFrom(to) == 0 {
-			g.Debug(dist, "** Found the destination (%s) at %d", c, dist)
-			// actually this is the end, so we don't need to explore further!
-			return
-		}
+// Returns the positions we can move to from p, for the search.
+func (g *Grid) Neighbors(p Pos) (rv []Pos) {
+	for _, c := range g.MoveOptions(p) {
+		rv = append(rv, c.id)
 	}
-
-	// Now recurse and explore
-	for _, c := range explore {
-		g.Explore(c.id, to, dist+1)
-	}
-
+	return
 }
 
 func main() {
@@ -226,9 +197,10 @@ func main() {
 	grid.Print()
 	fmt.Println()
 
-	end := grid.Cell(grid.end)
-	start := grid.Cell(grid.start)
-
-	grid.Explore(start.id, end.id, 1)
-	fmt.Println(end.dist)
+	g := search.Graph[Pos]{Neighbors: grid.Neighbors}
+	r := g.BFS(func(p Pos) bool { return p == grid.end }, grid.start)
+	if !r.Found {
+		log.Fatalf("no path from %s to %s", grid.start, grid.end)
+	}
+	fmt.Println(r.Distance)
 }
@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 12, Puzzle 2.
 // Hill Climbing Algorithm - Best Path.
 
@@ -11,6 +13,8 @@ import (
 	"log"
 	"os"
 	"strings"
+
+	"github.com/mattbnz/aoc/lib/search"
 )
 
 func Abs(x int) int {
@@ -49,9 +53,6 @@ func (p Pos) Down() Pos {
 type Cell struct {
 	id     Pos
 	height int
-
-	dTo   int // distance to this Cell (used to avoid backtracking)
-	dFrom int // distance from this Cell (the answer we want)
 }
 
 var Reset = "\033[0m"
@@ -178,36 +179,12 @@ func (g *Grid) Debug(dist int, msg string, args ...any) {
 	fmt.Println("")
 }
 
-func (g *Grid) Distance(fromP Pos, to Pos, dist int) int {
-	from := g.Cell(fromP)
-	if from.dFrom != 0 {
-		return from.dFrom
-	}
-	if from.dTo != 0 && from.dTo <= dist {
-		// Already been here... (looping)
-		return -1
+// Returns the positions we can move to from p, for the search.
+func (g *Grid) Neighbors(p Pos) (rv []Pos) {
+	for _, c := range g.MoveOptions(p) {
+		rv = append(rv, c.id)
 	}
-	g.Debug(dist, "%d: %s", dist, from)
-	from.dTo = dist
-
-	best := -1
-	for _, c := range g.MoveOptions(fromP) {
-		if c.id.DistFrom(to) == 0 {
-			g.Debug(dist, "%d: ** Found the destination (%s) from %s", dist, c, from)
-			// actually this is the end, so we don't need to explore further!
-			best = 0
-			break
-		}
-		d := g.Distance(c.id, to, dist+1)
-		if d != -1 && (best == -1 || d < best) {
-			best = d
-		}
-	}
-	if best == -1 {
-		return -1 // no path, dead end
-	}
-	from.dFrom = best + 1
-	return from.dFrom
+	return
 }
 
 func main() {
@@ -220,20 +197,16 @@ func main() {
 	grid.Print()
 	fmt.Println()
 
-	end := grid.Cell(grid.end)
-
-	rv := []int{}
+	// Searching from every start at once finds the closest.
+	starts := []Pos{}
 	for i := 0; i < len(grid.cells); i++ {
-		rv = append(rv, grid.Distance(Pos{row: i, col: 0}, end.id, 1))
+		starts = append(starts, Pos{row: i, col: 0})
 	}
-
-	min := rv[0]
-	for _, n := range rv {
-		fmt.Println(n)
-		if n < min {
-			min = n
-		}
+	g := search.Graph[Pos]{Neighbors: grid.Neighbors}
+	r := g.BFS(func(p Pos) bool { return p == grid.end }, starts...)
+	if !r.Found {
+		log.Fatalf("no path to %s", grid.end)
 	}
-	fmt.Println("")
-	fmt.Println(min)
+	fmt.Printf("From %s\n", r.Path[0])
+	fmt.Println(r.Distance)
 }
@@ -0,0 +1,7 @@
+// Advent of Code 2022 - Day 16.
+// Proboscidea Volcanium - pressure release.
+//
+// Each puzzle is a standalone program, built only when named explicitly:
+//
+//	go run pressure.go < input
+package day16
@@ -0,0 +1,7 @@
+module day16
+
+go 1.21.1
+
+require github.com/mattbnz/aoc/lib v0.0.0-00010101000000-000000000000
+
+replace github.com/mattbnz/aoc/lib => ../../lib
@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 16, Puzzle 1.
 // Proboscidea Volcanium - pressure release.
 
@@ -7,16 +9,17 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"math"
 	"os"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/mattbnz/aoc/lib/graph"
+	"github.com/mattbnz/aoc/lib/search"
 )
 
 func Int(s string) int {
@@ -27,42 +30,6 @@ func Int(s string) int {
 	return v
 }
 
-// A priority Queue entry.
-type PQE struct {
-	node string
-	dist int
-}
-
-// Priority Queue
-type PQ struct {
-	Q []PQE
-}
-
-func (q *PQ) Add(e PQE) {
-	if len(q.Q) == 0 {
-		q.Q = append(q.Q, e)
-		return
-	}
-	if e.dist < q.Q[0].dist {
-		q.Q = append([]PQE{e}, q.Q...)
-		return
-	}
-	for n, t := range q.Q {
-		if e.dist < t.dist {
-			q.Q = append(q.Q[:n+1], q.Q[n:]...)
-			q.Q[n] = e
-			return
-		}
-	}
-	q.Q = append(q.Q, e)
-}
-
-func (q *PQ) Pop() PQE {
-	e := q.Q[0]
-	q.Q = q.Q[1:]
-	return e
-}
-
 type Valve struct {
 	name     string
 	flowRate int
@@ -85,33 +52,19 @@ func (v Valve) Describe() string {
 	return fmt.Sprintf("Valve %s; flow rate=%d; leads to %s", v.name, v.flowRate, strings.Join(names, ", "))
 }
 
-// Calculates (Djikstra) distance to dest and stores into dists
+// Calculates distance to dest and stores into dists
 func (v *Valve) CalcDist(dest *Valve) {
 	if rev, ok := dest.dists[v.name]; ok {
 		v.dists[dest.name] = rev
 		return
 	}
 
-	dists := DjikstraStart()
-	been := map[string]bool{}
-	dists[v.name] = 0
-	q := PQ{}
-	q.Add(PQE{node: v.name, dist: 0})
-	for len(q.Q) > 0 {
-		c := q.Pop()
-		valve := Valves[c.node]
-		been[valve.name] = true
-		for _, n := range valve.paths {
-			if been[n.name] {
-				continue
-			}
-			if c.dist+1 < dists[n.name] {
-				dists[n.name] = c.dist + 1
-				q.Add(PQE{node: n.name, dist: c.dist + 1})
-			}
-		}
+	g := search.Graph[*Valve]{Neighbors: func(v *Valve) []*Valve { return v.paths }}
+	r := g.BFS(func(o *Valve) bool { return o == dest }, v)
+	if !r.Found {
+		log.Fatalf("No path from %s to %s", v, dest)
 	}
-	v.dists[dest.name] = dists[dest.name]
+	v.dists[dest.name] = r.Distance
 }
 
 // Returns the potential pressure released if valve opens at tick,
@@ -142,48 +95,24 @@ func GetOrCreateValves(nameList string) []*Valve {
 	return rv
 }
 
-func ShowDot(save bool) {
-	file, err := ioutil.TempFile("", "dot")
-	if err != nil {
-		log.Fatal(err)
-	}
+var graphFile = flag.String("graph", "", "also write the valve network to this .dot or .svg file")
 
-	file.WriteString("digraph {\n")
-	for _, v := range Valves {
-		file.WriteString(fmt.Sprintf("%s [label=\"%s flow=%d\"]\n", v.name, v.name, v.flowRate))
+// Returns the valve network as a graph, valves in name order.
+func ValveGraph() *graph.Graph {
+	names := []string{}
+	for name := range Valves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	g := graph.New("valves")
+	for _, name := range names {
+		v := Valves[name]
+		g.AddNode(v.name, fmt.Sprintf("%s flow=%d", v.name, v.flowRate))
 		for _, ov := range v.paths {
-			file.WriteString(fmt.Sprintf("%s -> %s\n", v.name, ov.name))
+			g.AddEdge(v.name, ov.name, "")
 		}
-		file.WriteString("\n")
-	}
-	file.WriteString("}\n")
-
-	cmd := exec.Command("dot", "-Tpng", "-o", file.Name()+".png", file.Name())
-	err = cmd.Run()
-	if err != nil {
-		log.Fatalf("Couldn't render %s: %v", file.Name(), err)
-		return
 	}
-	//os.Remove(file.Name())
-	if save {
-		log.Print("Image is at ", file.Name()+".png")
-	} else {
-		cmd = exec.Command("eog", file.Name()+".png")
-		err = cmd.Run()
-		if err != nil {
-			log.Fatalf("Couldn't display %s: %v", file.Name()+".png", err)
-			return
-		}
-		os.Remove(file.Name() + ".png")
-	}
-}
-
-func DjikstraStart() map[string]int {
-	rv := map[string]int{}
-	for n := range Valves {
-		rv[n] = math.MaxInt
-	}
-	return rv
+	return g
 }
 
 func IDKey(vl []*Valve) string {
@@ -254,6 +183,7 @@ var Priority []*Valve
 var Cache map[string]CE
 
 func main() {
+	flag.Parse()
 	s := bufio.NewScanner(os.Stdin)
 
 	Valves = map[string]*Valve{}
@@ -279,6 +209,11 @@ func main() {
 	sort.Slice(Priority, func(i, j int) bool {
 		return Priority[i].flowRate > Priority[j].flowRate
 	})
+	if *graphFile != "" {
+		if err := ValveGraph().WriteFile(*graphFile); err != nil {
+			log.Fatalf("Couldn't write %s: %v", *graphFile, err)
+		}
+	}
 
 	// Precompute how far from each other the valves are.
 	//fmt.Println(len(Priority), " useful valves ", Priority)
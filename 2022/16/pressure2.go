@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 16, Puzzle 2.
 // Proboscidea Volcanium - double pressure release.
 
@@ -8,11 +10,9 @@ package main
 import (
 	"bufio"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"math"
 	"os"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strconv"
@@ -149,42 +149,6 @@ func GetOrCreateValves(nameList string) []*Valve {
 	return rv
 }
 
-func ShowDot(save bool) {
-	file, err := ioutil.TempFile("", "dot")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	file.WriteString("digraph {\n")
-	for _, v := range Valves {
-		file.WriteString(fmt.Sprintf("%s [label=\"%s flow=%d\"]\n", v.name, v.name, v.flowRate))
-		for _, ov := range v.paths {
-			file.WriteString(fmt.Sprintf("%s -> %s\n", v.name, ov.name))
-		}
-		file.WriteString("\n")
-	}
-	file.WriteString("}\n")
-
-	cmd := exec.Command("dot", "-Tpng", "-o", file.Name()+".png", file.Name())
-	err = cmd.Run()
-	if err != nil {
-		log.Fatalf("Couldn't render %s: %v", file.Name(), err)
-		return
-	}
-	//os.Remove(file.Name())
-	if save {
-		log.Print("Image is at ", file.Name()+".png")
-	} else {
-		cmd = exec.Command("eog", file.Name()+".png")
-		err = cmd.Run()
-		if err != nil {
-			log.Fatalf("Couldn't display %s: %v", file.Name()+".png", err)
-			return
-		}
-		os.Remove(file.Name() + ".png")
-	}
-}
-
 func DjikstraStart() map[string]int {
 	rv := map[string]int{}
 	for n := range Valves {
@@ -0,0 +1,514 @@
+// Advent of Code 2022 - Day 17.
+// Pyroclastic Flow - tetris?
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run rocks.go < input). This package holds the falling rock
+// engine they share, where the pieces, chamber width, spawn position and
+// jets all come from a Config, so variants can be played with too.
+package day17
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// row 0 == floor, col 0 == left wall
+type Pos struct {
+	row, col int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d,%d", p.row, p.col)
+}
+
+const AIR = 0
+const ROCK = 1
+const FALLING_ROCK = 2
+
+const LEFT = -1
+const RIGHT = 1
+
+// Positions within piece are relative to the piece bottom-left (0,0), but arguments coming
+// in will be in column position, and need to be trasnformed.
+type Piece struct {
+	name   string      // convenience
+	top    int         // row of column this rock's top is at
+	left   int         // column of column this rock's left edge is at
+	height int         // height of piece, less one
+	width  int         // width of piece
+	pixels map[Pos]int // set of pixels that make up this rock, relative to bottom,left(0,0)
+}
+
+var OutsidePiece = errors.New("outside piece")
+
+func (p Piece) String() string {
+	return fmt.Sprintf("%s@%d", p.name, p.top)
+}
+
+// Convert an absolute (column) position, into a relative position in this item
+func (p Piece) AbsToRel(pos Pos) (Pos, error) {
+	if pos.row >= (p.top-p.height) && pos.row <= p.top {
+		return Pos{row: (pos.row - p.top) + p.height, col: pos.col - p.left}, nil
+	}
+	return Pos{}, OutsidePiece
+}
+
+// Convert an relative position in this item into a absolute (column) posotion
+func (p Piece) RelToAbs(pos Pos) Pos {
+	return Pos{row: (pos.row + p.top) - p.height, col: pos.col + p.left}
+}
+
+func (p *Piece) C(pos Pos) (int, error) {
+	rpos, err := p.AbsToRel(pos)
+	if err != nil {
+		return -1, err
+	}
+	return p.pixels[rpos], nil
+}
+
+func (p Piece) AbsPixels() []Pos {
+	rv := []Pos{}
+	for pos := range p.pixels {
+		rv = append(rv, p.RelToAbs(pos))
+	}
+	return rv
+}
+
+// Builds a piece from its pixels, which are moved so its lowest is in row 0
+// and its leftmost in col 0.
+func NewPiece(n string, p []Pos) (Piece, error) {
+	if len(p) == 0 {
+		return Piece{}, fmt.Errorf("piece %s has no pixels", n)
+	}
+	minRow, minCol := p[0].row, p[0].col
+	for _, t := range p {
+		minRow, minCol = min(minRow, t.row), min(minCol, t.col)
+	}
+	np := Piece{name: n, pixels: map[Pos]int{}}
+	for _, t := range p {
+		t = Pos{t.row - minRow, t.col - minCol}
+		np.pixels[t] = FALLING_ROCK
+		np.height = max(np.height, t.row)
+		np.width = max(np.width, t.col+1)
+	}
+	return np, nil
+}
+
+// Parses a piece drawn as rows of # (rock) and . (air), top row first.
+func ParsePiece(n string, rows []string) (Piece, error) {
+	pixels := []Pos{}
+	for r, row := range rows {
+		for c, ch := range row {
+			switch ch {
+			case '#':
+				pixels = append(pixels, Pos{len(rows) - 1 - r, c})
+			case '.':
+			default:
+				return Piece{}, fmt.Errorf("bad char %c in piece %s", ch, n)
+			}
+		}
+	}
+	return NewPiece(n, pixels)
+}
+
+func ParseJets(in string) ([]int, error) {
+	rv := []int{}
+	for n, c := range strings.TrimSpace(in) {
+		if c == '<' {
+			rv = append(rv, LEFT)
+		} else if c == '>' {
+			rv = append(rv, RIGHT)
+		} else {
+			return nil, fmt.Errorf("bad jet at char %d: %c", n, c)
+		}
+	}
+	return rv, nil
+}
+
+// Config describes a chamber and what falls into it.
+type Config struct {
+	Width int // of the chamber, between the walls
+	// Where each rock appears: Left columns clear of the left wall, and
+	// Gap rows clear of the highest rock (or the floor).
+	Left, Gap int
+
+	Pieces []Piece // dropped in turn
+	Jets   []int   // LEFT or RIGHT, used in turn
+}
+
+// The puzzle's chamber, in the format ParseConfig reads.
+const PuzzleConfig = `width 7
+spawn 2 3
+piece hline
+####
+piece plus
+.#.
+###
+.#.
+piece badl
+..#
+..#
+###
+piece vline
+#
+#
+#
+#
+piece square
+##
+##
+`
+
+// Returns the puzzle's chamber, with the given jets.
+func Puzzle(jets []int) Config {
+	cfg, err := ParseConfig(strings.NewReader(PuzzleConfig))
+	if err != nil {
+		panic(err)
+	}
+	cfg.Jets = jets
+	return cfg
+}
+
+// Reads a config, made of lines:
+//
+//	width <columns>
+//	spawn <left> <gap>
+//	jets <jets, e.g. >><>
+//	piece <name>
+//
+// where each piece line is followed by the rows of the piece, top first,
+// drawn with # and . (see PuzzleConfig). Blank lines, and lines starting
+// with //, are ignored.
+func ParseConfig(r io.Reader) (cfg Config, err error) {
+	s := bufio.NewScanner(r)
+	var piece string
+	var rows []string
+	endPiece := func() error {
+		if piece == "" {
+			return nil
+		}
+		p, err := ParsePiece(piece, rows)
+		if err != nil {
+			return err
+		}
+		cfg.Pieces = append(cfg.Pieces, p)
+		piece, rows = "", nil
+		return nil
+	}
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "//") {
+			continue
+		}
+		if l[0] == '#' || l[0] == '.' {
+			if piece == "" {
+				return cfg, fmt.Errorf("piece row %q outside a piece", l)
+			}
+			rows = append(rows, l)
+			continue
+		}
+		if err := endPiece(); err != nil {
+			return cfg, err
+		}
+		f := strings.Fields(l)
+		switch {
+		case f[0] == "width" && len(f) == 2:
+			cfg.Width, err = strconv.Atoi(f[1])
+		case f[0] == "spawn" && len(f) == 3:
+			if cfg.Left, err = strconv.Atoi(f[1]); err == nil {
+				cfg.Gap, err = strconv.Atoi(f[2])
+			}
+		case f[0] == "jets" && len(f) == 2:
+			cfg.Jets, err = ParseJets(f[1])
+		case f[0] == "piece" && len(f) == 2:
+			piece = f[1]
+		default:
+			return cfg, fmt.Errorf("bad config line: %s", l)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("bad config line: %s: %v", l, err)
+		}
+	}
+	if err := endPiece(); err != nil {
+		return cfg, err
+	}
+	return cfg, s.Err()
+}
+
+// Returns the config in filename, or the puzzle's chamber if filename is
+// empty. jets are used if the config doesn't have its own.
+func LoadConfig(filename string, jets []int) (Config, error) {
+	if filename == "" {
+		return Puzzle(jets), nil
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	cfg, err := ParseConfig(f)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	if len(cfg.Jets) == 0 {
+		cfg.Jets = jets
+	}
+	return cfg, nil
+}
+
+func (cfg Config) Check() error {
+	if cfg.Width < 1 {
+		return fmt.Errorf("chamber width %d is too narrow", cfg.Width)
+	}
+	if cfg.Left < 0 || cfg.Gap < 0 {
+		return fmt.Errorf("bad spawn position %d,%d", cfg.Left, cfg.Gap)
+	}
+	if len(cfg.Pieces) == 0 {
+		return fmt.Errorf("no pieces")
+	}
+	for _, p := range cfg.Pieces {
+		if cfg.Left+p.width > cfg.Width {
+			return fmt.Errorf("%s doesn't fit in the chamber", p.name)
+		}
+	}
+	if len(cfg.Jets) == 0 {
+		return fmt.Errorf("no jets")
+	}
+	return nil
+}
+
+type Column struct {
+	cfg Config
+
+	// last final state, doesn't include current piece
+	c      map[Pos]int
+	toprow int // topmost row in c
+
+	// the piece currently falling
+	piece Piece
+
+	rock int // rocks dropped so far
+	move int // jets used so far
+}
+
+func NewColumn(cfg Config) (*Column, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	return &Column{cfg: cfg, c: map[Pos]int{}}, nil
+}
+
+// Height of the tower of rocks.
+func (c *Column) Height() int {
+	return c.toprow
+}
+
+func (c *Column) Print() {
+	fmt.Print(c)
+}
+
+// Draws the top of the column, down to 10 rows below the highest rock.
+func (c *Column) String() string {
+	top := c.toprow
+	if c.piece.top != 0 {
+		top = c.piece.top
+	}
+	var b strings.Builder
+	for row := top; row >= max(c.toprow-10, 0); row-- {
+		for col := 0; col < c.cfg.Width+2; col++ {
+			// borders
+			if row == 0 {
+				if col == 0 || col == c.cfg.Width+1 {
+					b.WriteString("+")
+				} else {
+					b.WriteString("-")
+				}
+				continue
+			}
+			if col == 0 || col == c.cfg.Width+1 {
+				b.WriteString("|")
+				continue
+			}
+			// playing space
+			t := c.C(Pos{row, col}, true)
+			if t == ROCK {
+				b.WriteString("#")
+			} else if t == FALLING_ROCK {
+				b.WriteString("@")
+			} else {
+				b.WriteString(".")
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (c *Column) C(p Pos, usePiece bool) int {
+	if usePiece && c.piece.top != 0 {
+		if t, err := c.piece.C(p); err == nil && t != AIR {
+			return t
+		}
+	}
+	return c.c[p]
+}
+
+func (c *Column) Push(dir int) {
+	np := c.piece
+	np.left += dir
+	if np.left < 1 || np.left+np.width > c.cfg.Width+1 {
+		return
+	}
+	if c.TouchesRock(np) {
+		return
+	}
+	c.piece = np
+}
+
+func (c *Column) Drop() bool {
+	np := c.piece
+	np.top--
+	if np.top-np.height == 0 || c.TouchesRock(np) {
+		// can't drop, merge where is and stop
+		c.Merge()
+		return true
+	}
+	c.piece = np
+	return false
+}
+
+func (c *Column) TouchesRock(p Piece) bool {
+	for _, pos := range p.AbsPixels() {
+		if c.C(pos, false) != AIR {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Column) Merge() {
+	for _, pos := range c.piece.AbsPixels() {
+		c.c[pos] = ROCK
+		c.toprow = max(c.toprow, pos.row)
+	}
+	c.piece = Piece{}
+}
+
+// Inserts a new piece at it's default location
+func (c *Column) New(p Piece) {
+	p.top = c.toprow + c.cfg.Gap + 1 + p.height
+	p.left = c.cfg.Left + 1
+	c.piece = p
+}
+
+// Drops the next rock until it comes to rest, and returns the state after.
+func (c *Column) DropRock() State {
+	block := c.rock % len(c.cfg.Pieces)
+	c.New(c.cfg.Pieces[block])
+	for {
+		c.Push(c.cfg.Jets[c.move%len(c.cfg.Jets)])
+		c.move++
+		if c.Drop() {
+			break
+		}
+	}
+	s := State{rock: c.rock, height: c.toprow, nextgas: c.move % len(c.cfg.Jets), block: block}
+	c.rock++
+	return s
+}
+
+// Drops rocks until n have fallen, and returns the height of the tower.
+func (c *Column) Run(n int) int {
+	for c.rock < n {
+		c.DropRock()
+	}
+	return c.toprow
+}
+
+type State struct {
+	rock    int // which rock this state is for
+	height  int // height after block below dropped
+	nextgas int // gas that will hit *next* block
+	block   int // block that *just* dropped
+}
+
+func (s State) String() string {
+	return fmt.Sprintf("r%d,h%d,b%d,g%d", s.rock, s.height, s.block, s.nextgas)
+}
+
+// two states match if their block/nextgas are the same, and their heights differ by delta
+func (s State) Matches(o State, delta int) bool {
+	if s.nextgas != o.nextgas {
+		return false
+	}
+	if s.block != o.block {
+		return false
+	}
+	if s.Delta(o) != delta {
+		return false
+	}
+	return true
+}
+
+// Returns the (absolute) height delta between the two states
+func (s State) Delta(o State) int {
+	h := s.height - o.height
+	if h < 0 {
+		return h * -1
+	}
+	return h
+
+}
+
+// Cycle is a run of rocks that keeps repeating, growing the tower by Delta
+// each time.
+type Cycle struct {
+	Start State // first state of the three in a row that matched
+	Len   int
+	Delta int
+}
+
+func (c Cycle) String() string {
+	return fmt.Sprintf("len=%d,delta=%d from %s", c.Len, c.Delta, c.Start)
+}
+
+// Returns the height of the tower after wanted rocks, dropping rocks into
+// a new column only until three runs of the same length match, then
+// extrapolating. found is false if wanted rocks fell before that happened.
+func Extrapolate(cfg Config, wanted int) (height int, cycle Cycle, found bool, err error) {
+	col, err := NewColumn(cfg)
+	if err != nil {
+		return 0, Cycle{}, false, err
+	}
+	states := []State{} // state after each rock
+	search := []int{}   // sizes we're still evaluating for patterns
+
+	for rock := 0; rock < wanted; rock++ {
+		if rock > len(cfg.Pieces) {
+			search = append(search, rock)
+		}
+		states = append(states, col.DropRock())
+		for _, l := range search {
+			if rock < l*2 {
+				continue
+			}
+			h1 := states[rock]
+			h2 := states[rock-l]
+			h3 := states[rock-(l*2)]
+			delta := h1.Delta(h2)
+			if h1.Matches(h2, delta) && h2.Matches(h3, delta) {
+				gap := (wanted - 1) - h3.rock
+				baseHeightRow := (wanted - 1) - l*(gap/l)
+				height = states[baseHeightRow].height + delta*(gap/l)
+				return height, Cycle{Start: h3, Len: l, Delta: delta}, true, nil
+			}
+		}
+	}
+	return col.Height(), Cycle{}, false, nil
+}
@@ -0,0 +1,134 @@
+package day17
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleJets(t *testing.T) []int {
+	b, err := os.ReadFile("sample")
+	require.NoError(t, err)
+	jets, err := ParseJets(string(b))
+	require.NoError(t, err)
+	return jets
+}
+
+func Test_ParseConfig(t *testing.T) {
+	cfg := Puzzle(nil)
+	assert.Equal(t, 7, cfg.Width)
+	assert.Equal(t, 2, cfg.Left)
+	assert.Equal(t, 3, cfg.Gap)
+	require.Equal(t, 5, len(cfg.Pieces))
+	plus := cfg.Pieces[1]
+	assert.Equal(t, "plus", plus.name)
+	assert.Equal(t, 2, plus.height)
+	assert.Equal(t, 3, plus.width)
+	assert.Equal(t, map[Pos]int{{0, 1}: FALLING_ROCK, {1, 0}: FALLING_ROCK, {1, 1}: FALLING_ROCK, {1, 2}: FALLING_ROCK, {2, 1}: FALLING_ROCK}, plus.pixels)
+	assert.Equal(t, 3, cfg.Pieces[3].height)
+	assert.Equal(t, 1, cfg.Pieces[3].width)
+
+	cfg, err := ParseConfig(strings.NewReader("// comment\nwidth 4\nspawn 0 1\njets <>>\n\npiece dot\n#\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{LEFT, RIGHT, RIGHT}, cfg.Jets)
+	assert.NoError(t, cfg.Check())
+
+	for _, bad := range []string{
+		"width x",
+		"spawn 1",
+		"jets <x>",
+		"##",
+		"piece a\n#x",
+		"piece a\n...",
+		"colour red",
+	} {
+		_, err := ParseConfig(strings.NewReader(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func Test_Check(t *testing.T) {
+	jets := []int{LEFT}
+	assert.NoError(t, Puzzle(jets).Check())
+	assert.Error(t, Puzzle(nil).Check())
+
+	cfg := Puzzle(jets)
+	cfg.Width = 5 // hline doesn't fit 2 in from the wall
+	assert.Error(t, cfg.Check())
+	cfg.Left = 1
+	assert.NoError(t, cfg.Check())
+	cfg.Pieces = nil
+	assert.Error(t, cfg.Check())
+}
+
+func Test_Sample(t *testing.T) {
+	cfg := Puzzle(sampleJets(t))
+	col, err := NewColumn(cfg)
+	require.NoError(t, err)
+	col.DropRock()
+	assert.Equal(t, "|..####.|\n+-------+\n\n", col.String())
+	col.DropRock()
+	assert.Equal(t, 4, col.Height())
+	assert.Equal(t, 3068, col.Run(2022))
+
+	height, cycle, found, err := Extrapolate(cfg, 1000000000000)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 35, cycle.Len)
+	assert.Equal(t, 53, cycle.Delta)
+	assert.Equal(t, 1514285714288, height)
+}
+
+// Extrapolating agrees with dropping every rock, whatever falls into
+// whatever chamber.
+func Test_Variants(t *testing.T) {
+	jets := sampleJets(t)
+	for name, config := range map[string]string{
+		"puzzle": PuzzleConfig,
+		"narrow": "width 5\nspawn 1 3\npiece bar\n###\npiece ell\n#.\n##\n",
+		"wide":   "width 11\nspawn 4 2\npiece hline\n####\npiece plus\n.#.\n###\n.#.\npiece dot\n#\n",
+		"low":    "width 7\nspawn 0 0\npiece hole\n#.#\n###\npiece square\n##\n##\n",
+		"jets":   "width 6\nspawn 2 3\njets <<<>>><>\npiece zed\n##.\n.##\npiece ess\n.##\n##.\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := ParseConfig(strings.NewReader(config))
+			require.NoError(t, err)
+			if len(cfg.Jets) == 0 {
+				cfg.Jets = jets
+			}
+			col, err := NewColumn(cfg)
+			require.NoError(t, err)
+			heights := []int{0}
+			for n := 0; n < 4000; n++ {
+				col.DropRock()
+				heights = append(heights, col.Height())
+			}
+			for _, n := range []int{0, 1, 7, 1000, 2022, 3999, 4000} {
+				height, _, found, err := Extrapolate(cfg, n)
+				require.NoError(t, err)
+				assert.Equal(t, heights[n], height, "after %d rocks (cycle found: %v)", n, found)
+			}
+			_, _, found, err := Extrapolate(cfg, 1000000000000)
+			require.NoError(t, err)
+			assert.True(t, found)
+		})
+	}
+}
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	fn := dir + "/narrow"
+	require.NoError(t, os.WriteFile(fn, []byte("width 3\nspawn 0 3\npiece dot\n#\n"), 0644))
+	cfg, err := LoadConfig(fn, []int{RIGHT})
+	require.NoError(t, err)
+	col, err := NewColumn(cfg)
+	require.NoError(t, err)
+	// Every dot is pushed to the right wall and stacks up there.
+	assert.Equal(t, 10, col.Run(10))
+
+	_, err = LoadConfig(dir+"/missing", nil)
+	assert.Error(t, err)
+}
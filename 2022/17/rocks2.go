@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 17, Puzzle 2.
 // Pyroclastic Flow - giant tetris...
 
@@ -0,0 +1,279 @@
+// Advent of Code 2022 - Day 21.
+// Monkey Math. Algebra Solver...
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run monkeymath2.go < input). This package turns the
+// monkeys into an expression tree over exact rationals, so that it can be
+// simplified and solved for whichever monkey is unknown.
+package day21
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+type Monkey struct {
+	Name   string
+	Number int64 // for announcing monkeys
+	// for computing monkeys
+	Left  string
+	Op    string
+	Right string
+}
+
+func (m *Monkey) String() string {
+	if m.Op == "" {
+		return fmt.Sprintf("%s: %d", m.Name, m.Number)
+	}
+	return fmt.Sprintf("%s: %s %s %s", m.Name, m.Left, m.Op, m.Right)
+}
+
+var MATH_MONKEY_RE = regexp.MustCompile(`^([a-z]+): ([a-z]+) ([+*/-]) ([a-z]+)$`)
+var ANNOUNCING_MONKEY_RE = regexp.MustCompile(`^([a-z]+): (-?\d+)$`)
+
+func NewMonkey(s string) (*Monkey, error) {
+	if m := MATH_MONKEY_RE.FindStringSubmatch(s); m != nil {
+		return &Monkey{Name: m[1], Left: m[2], Op: m[3], Right: m[4]}, nil
+	}
+	if m := ANNOUNCING_MONKEY_RE.FindStringSubmatch(s); m != nil {
+		v, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad monkey number: %s: %v", s, err)
+		}
+		return &Monkey{Name: m[1], Number: v}, nil
+	}
+	return nil, fmt.Errorf("bad monkey: %s", s)
+}
+
+type MonkeyMap map[string]*Monkey
+
+func ReadMonkeys(r io.Reader) (MonkeyMap, error) {
+	monkeys := MonkeyMap{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		m, err := NewMonkey(s.Text())
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := monkeys[m.Name]; dup {
+			return nil, fmt.Errorf("%s appears twice", m.Name)
+		}
+		monkeys[m.Name] = m
+	}
+	return monkeys, s.Err()
+}
+
+// Expr is a node in the expression tree of what a monkey yells.
+type Expr interface {
+	String() string
+}
+
+// Const is a value that is already known.
+type Const struct {
+	V *big.Rat
+}
+
+func (c Const) String() string {
+	return c.V.RatString()
+}
+
+// Unknown is the monkey being solved for.
+type Unknown struct {
+	Name string
+}
+
+func (u Unknown) String() string {
+	return u.Name
+}
+
+// BinOp is an operation with the unknown somewhere beneath it.
+type BinOp struct {
+	Op   string
+	L, R Expr
+}
+
+func (b BinOp) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.L, b.Op, b.R)
+}
+
+func calc(a *big.Rat, op string, b *big.Rat) (*big.Rat, error) {
+	r := new(big.Rat)
+	switch op {
+	case "+":
+		return r.Add(a, b), nil
+	case "-":
+		return r.Sub(a, b), nil
+	case "*":
+		return r.Mul(a, b), nil
+	case "/":
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero: %s / 0", a.RatString())
+		}
+		return r.Quo(a, b), nil
+	}
+	return nil, fmt.Errorf("bad op %q", op)
+}
+
+// Returns what the named monkey yells as an expression of unknown (which
+// may be "" to have none). Anything not depending on unknown is worked out
+// to a Const.
+func (mm MonkeyMap) Expr(name, unknown string) (Expr, error) {
+	return mm.expr(name, unknown, map[string]bool{})
+}
+
+func (mm MonkeyMap) expr(name, unknown string, busy map[string]bool) (Expr, error) {
+	if name == unknown {
+		return Unknown{name}, nil
+	}
+	m, found := mm[name]
+	if !found {
+		return nil, fmt.Errorf("no monkey %s", name)
+	}
+	if m.Op == "" {
+		return Const{new(big.Rat).SetInt64(m.Number)}, nil
+	}
+	if busy[name] {
+		return nil, fmt.Errorf("%s depends on itself", name)
+	}
+	busy[name] = true
+	defer delete(busy, name)
+
+	l, err := mm.expr(m.Left, unknown, busy)
+	if err != nil {
+		return nil, err
+	}
+	r, err := mm.expr(m.Right, unknown, busy)
+	if err != nil {
+		return nil, err
+	}
+	lc, lok := l.(Const)
+	rc, rok := r.(Const)
+	if lok && rok {
+		v, err := calc(lc.V, m.Op, rc.V)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m, err)
+		}
+		return Const{v}, nil
+	}
+	return BinOp{m.Op, l, r}, nil
+}
+
+// Returns what the named monkey yells.
+func (mm MonkeyMap) Value(name string) (*big.Rat, error) {
+	e, err := mm.Expr(name, "")
+	if err != nil {
+		return nil, err
+	}
+	return e.(Const).V, nil
+}
+
+var (
+	ErrNonLinear = errors.New("unknown is not linear")
+	ErrBothSides = errors.New("unknown is on both sides")
+	ErrNoUnknown = errors.New("unknown is on neither side")
+)
+
+// Returns a and b such that e = a*unknown + b, or ErrNonLinear.
+func linear(e Expr) (a, b *big.Rat, err error) {
+	switch e := e.(type) {
+	case Const:
+		return new(big.Rat), e.V, nil
+	case Unknown:
+		return big.NewRat(1, 1), new(big.Rat), nil
+	case BinOp:
+		la, lb, err := linear(e.L)
+		if err != nil {
+			return nil, nil, err
+		}
+		ra, rb, err := linear(e.R)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch e.Op {
+		case "+", "-":
+			a, _ = calc(la, e.Op, ra)
+			b, _ = calc(lb, e.Op, rb)
+			return a, b, nil
+		case "*":
+			if la.Sign() != 0 && ra.Sign() != 0 {
+				return nil, nil, fmt.Errorf("%w: %s", ErrNonLinear, e)
+			}
+			// (la*x + lb) * (ra*x + rb), where one of la, ra is zero.
+			a = new(big.Rat).Add(new(big.Rat).Mul(la, rb), new(big.Rat).Mul(ra, lb))
+			return a, new(big.Rat).Mul(lb, rb), nil
+		case "/":
+			if ra.Sign() != 0 {
+				return nil, nil, fmt.Errorf("%w: %s", ErrNonLinear, e)
+			}
+			if rb.Sign() == 0 {
+				return nil, nil, fmt.Errorf("division by zero: %s", e)
+			}
+			return new(big.Rat).Quo(la, rb), new(big.Rat).Quo(lb, rb), nil
+		}
+		return nil, nil, fmt.Errorf("bad op %q", e.Op)
+	}
+	return nil, nil, fmt.Errorf("bad expression %v", e)
+}
+
+func contains(e Expr) bool {
+	_, known := e.(Const)
+	return !known
+}
+
+// Returns the value unknown must be for l and r to be equal. The unknown
+// must appear, linearly, on exactly one side.
+func Solve(l, r Expr) (*big.Rat, error) {
+	if contains(l) && contains(r) {
+		return nil, fmt.Errorf("%w: %s = %s", ErrBothSides, l, r)
+	}
+	if contains(r) {
+		l, r = r, l
+	}
+	if !contains(l) {
+		return nil, fmt.Errorf("%w: %s = %s", ErrNoUnknown, l, r)
+	}
+	a, b, err := linear(l)
+	if err != nil {
+		return nil, err
+	}
+	if a.Sign() == 0 {
+		return nil, fmt.Errorf("%s doesn't depend on the unknown", l)
+	}
+	// a*x + b = r
+	x := new(big.Rat).Sub(r.(Const).V, b)
+	return x.Quo(x, a), nil
+}
+
+// Returns both sides of root's equation as expressions of unknown.
+func (mm MonkeyMap) Equation(unknown string) (l, r Expr, err error) {
+	root, found := mm["root"]
+	if !found || root.Op == "" {
+		return nil, nil, fmt.Errorf("no root monkey with an equation")
+	}
+	if l, err = mm.Expr(root.Left, unknown); err != nil {
+		return
+	}
+	r, err = mm.Expr(root.Right, unknown)
+	return
+}
+
+// Returns what the named monkey needs to yell for both sides of root's
+// equation to match.
+func (mm MonkeyMap) SolveFor(unknown string) (*big.Rat, error) {
+	if unknown == "root" {
+		return nil, fmt.Errorf("can't solve for root, it is the equation")
+	}
+	if _, found := mm[unknown]; !found {
+		return nil, fmt.Errorf("no monkey %s", unknown)
+	}
+	l, r, err := mm.Equation(unknown)
+	if err != nil {
+		return nil, err
+	}
+	return Solve(l, r)
+}
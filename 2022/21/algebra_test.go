@@ -0,0 +1,152 @@
+package day21
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func load(t *testing.T, filename string) MonkeyMap {
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	monkeys, err := ReadMonkeys(f)
+	require.NoError(t, err)
+	return monkeys
+}
+
+func parse(t *testing.T, lines ...string) MonkeyMap {
+	monkeys, err := ReadMonkeys(strings.NewReader(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+	return monkeys
+}
+
+func Test_Sample(t *testing.T) {
+	monkeys := load(t, "sample")
+	v, err := monkeys.Value("root")
+	require.NoError(t, err)
+	assert.Equal(t, "152", v.RatString())
+
+	l, r, err := monkeys.Equation("humn")
+	require.NoError(t, err)
+	assert.Equal(t, "((4 + (2 * (humn - 3))) / 4)", l.String())
+	assert.Equal(t, "150", r.String())
+
+	x, err := monkeys.SolveFor("humn")
+	require.NoError(t, err)
+	assert.Equal(t, "301", x.RatString())
+}
+
+// Solving for a monkey, then having it yell the answer, balances root.
+func Test_SolveForAny(t *testing.T) {
+	for name, want := range map[string]string{
+		"humn": "301",
+		"dvpt": "-293",
+		"ptdq": "298",   // a computing monkey, its own sum is ignored
+		"sjmn": "2",     // on the right hand side
+		"zczc": "158/5", // not a whole number
+	} {
+		monkeys := load(t, "sample")
+		x, err := monkeys.SolveFor(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, x.RatString(), name)
+
+		l, r, err := monkeys.Equation(name)
+		require.NoError(t, err)
+		assert.Equal(t, 0, eval(t, substitute(l, x)).Cmp(eval(t, substitute(r, x))), name)
+	}
+}
+
+// Replaces the unknown in e with x.
+func substitute(e Expr, x *big.Rat) Expr {
+	switch e := e.(type) {
+	case Unknown:
+		return Const{x}
+	case BinOp:
+		return BinOp{e.Op, substitute(e.L, x), substitute(e.R, x)}
+	}
+	return e
+}
+
+func eval(t *testing.T, e Expr) *big.Rat {
+	switch e := e.(type) {
+	case Const:
+		return e.V
+	case BinOp:
+		v, err := calc(eval(t, e.L), e.Op, eval(t, e.R))
+		require.NoError(t, err)
+		return v
+	}
+	t.Fatalf("can't evaluate %s", e)
+	return nil
+}
+
+func Test_Errors(t *testing.T) {
+	monkeys := parse(t,
+		"root: aaaa + bbbb",
+		"aaaa: humn * humn",
+		"bbbb: 4",
+		"cccc: bbbb / humn",
+		"dddd: humn + humn",
+		"eeee: humn - humn",
+		"humn: 3",
+	)
+	_, err := monkeys.SolveFor("humn")
+	assert.True(t, errors.Is(err, ErrNonLinear), err)
+	_, err = monkeys.SolveFor("root")
+	assert.Error(t, err)
+	_, err = monkeys.SolveFor("zzzz")
+	assert.Error(t, err)
+
+	// The unknown can appear more than once, as long as it stays linear.
+	monkeys["root"].Left = "dddd"
+	x, err := monkeys.SolveFor("humn")
+	require.NoError(t, err)
+	assert.Equal(t, "2", x.RatString())
+
+	monkeys["root"].Left = "cccc"
+	_, err = monkeys.SolveFor("humn")
+	assert.True(t, errors.Is(err, ErrNonLinear), err)
+
+	monkeys["root"].Left = "eeee"
+	_, err = monkeys.SolveFor("humn")
+	assert.Error(t, err)
+
+	monkeys["root"].Left, monkeys["root"].Right = "dddd", "dddd"
+	_, err = monkeys.SolveFor("humn")
+	assert.True(t, errors.Is(err, ErrBothSides), err)
+
+	_, err = monkeys.SolveFor("bbbb")
+	assert.True(t, errors.Is(err, ErrNoUnknown), err)
+
+	monkeys = parse(t, "root: aaaa + bbbb", "aaaa: bbbb * humn", "bbbb: aaaa - humn", "humn: 1")
+	_, err = monkeys.Value("root")
+	assert.Error(t, err)
+	monkeys = parse(t, "root: aaaa + bbbb", "aaaa: 1", "bbbb: aaaa / zero", "zero: 0")
+	_, err = monkeys.Value("root")
+	assert.Error(t, err)
+
+	// Dividing by the unknown isn't linear.
+	_, err = load(t, "sample").SolveFor("lfqf")
+	assert.True(t, errors.Is(err, ErrNonLinear), err)
+
+	_, err = ReadMonkeys(strings.NewReader("root: aaaa % bbbb"))
+	assert.Error(t, err)
+}
+
+func Test_Part1(t *testing.T) {
+	v, err := load(t, "input").Value("root")
+	require.NoError(t, err)
+	assert.Equal(t, "87457751482938", v.RatString())
+}
+
+func Test_Part2(t *testing.T) {
+	x, err := load(t, "input").SolveFor("humn")
+	require.NoError(t, err)
+	assert.Equal(t, "3221245824363", x.RatString())
+}
@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 21, Puzzle 2.
 // // Monkey Math. Algebra Solver...
 
@@ -0,0 +1,374 @@
+// Advent of Code 2022 - Day 22.
+// Monkey Map. Path following on a cube!
+//
+// The puzzles themselves are standalone programs, built only when named
+// explicitly (go run monkeymap2.go < input). This package holds the cube
+// folding used by part 2, which works out how the edges join up from
+// whichever of the 11 cube nets the input is drawn as.
+package day22
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Basic position type, used in three ways
+// 1) To reference cells in the input, 1 based (called "Absolute position")
+// 2) To reference cells on a side, 0 based (called "Relative position")
+// 3) To reference sides in the input (called "Side position")
+type Pos struct {
+	Row, Col int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d,%d", p.Row, p.Col)
+}
+
+// What can go in each position
+type Content int
+
+const (
+	VOID Content = iota
+	OPEN
+	WALL
+)
+
+// Directions of movement, valued as the puzzle scores them.
+type Direction int
+
+const (
+	D_RIGHT Direction = iota
+	D_DOWN
+	D_LEFT
+	D_UP
+)
+
+var DIRECTIONS = []Direction{D_RIGHT, D_DOWN, D_LEFT, D_UP}
+
+func (d Direction) String() string {
+	return []string{">", "v", "<", "^"}[d]
+}
+
+// Returns the direction after turning "L" or "R".
+func (d Direction) Turn(lr string) Direction {
+	if lr == "L" {
+		return (d + 3) % 4
+	}
+	return (d + 1) % 4
+}
+
+func (d Direction) Reverse() Direction {
+	return (d + 2) % 4
+}
+
+// Returns p moved one step in direction d.
+func (p Pos) Step(d Direction) Pos {
+	switch d {
+	case D_RIGHT:
+		return Pos{p.Row, p.Col + 1}
+	case D_DOWN:
+		return Pos{p.Row + 1, p.Col}
+	case D_LEFT:
+		return Pos{p.Row, p.Col - 1}
+	}
+	return Pos{p.Row - 1, p.Col}
+}
+
+// A direction in 3D space, for working out how the sides fold together.
+type vec [3]int
+
+func (v vec) neg() vec {
+	return vec{-v[0], -v[1], -v[2]}
+}
+
+func (v vec) add(o vec, scale int) vec {
+	return vec{v[0] + o[0]*scale, v[1] + o[1]*scale, v[2] + o[2]*scale}
+}
+
+func (v vec) dot(o vec) int {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+// A side of the cube
+type Side struct {
+	SidePos Pos // Side position in the input grid
+	Size    int
+
+	// zero based relative co-ordinates on this side, use Abs() to get
+	// back to input co-ords.
+	c map[Pos]Content
+
+	// Where the side ended up once folded: the outward facing normal, and
+	// which way its columns and rows run.
+	normal, right, down vec
+	folded              bool
+}
+
+func (s *Side) String() string {
+	return fmt.Sprintf("side %s (facing %v)", s.SidePos, s.normal)
+}
+
+func (s *Side) C(rel Pos) Content {
+	return s.c[rel]
+}
+
+// Returns the absolute co-ordinates of rel on this side.
+func (s *Side) Abs(rel Pos) Pos {
+	return Pos{s.SidePos.Row*s.Size + 1 + rel.Row, s.SidePos.Col*s.Size + 1 + rel.Col}
+}
+
+// The direction d on the side points this way in 3D.
+func (s *Side) vec(d Direction) vec {
+	switch d {
+	case D_RIGHT:
+		return s.right
+	case D_DOWN:
+		return s.down
+	case D_LEFT:
+		return s.right.neg()
+	}
+	return s.down.neg()
+}
+
+// The direction on the side that points along v.
+func (s *Side) heading(v vec) Direction {
+	for _, d := range DIRECTIONS {
+		if s.vec(d) == v {
+			return d
+		}
+	}
+	panic(fmt.Sprintf("%v is not in the plane of %s", v, s))
+}
+
+// The centre of the cell at rel, in 3D, with the cube running from -Size to
+// Size on each axis so that cell centres land on whole numbers.
+func (s *Side) centre(rel Pos) vec {
+	return vec{}.add(s.normal, s.Size).add(s.right, 2*rel.Col-(s.Size-1)).add(s.down, 2*rel.Row-(s.Size-1))
+}
+
+// A cube.
+type Cube struct {
+	Size int
+
+	sides  map[Pos]*Side // by side position in the input
+	facing map[vec]*Side // by outward normal once folded
+}
+
+// Position on the cube
+type CubePos struct {
+	Side    *Side
+	RelPos  Pos
+	Heading Direction
+}
+
+func (cp CubePos) String() string {
+	return fmt.Sprintf("%s/%s going %s", cp.Side, cp.RelPos, cp.Heading)
+}
+
+// Returns an absolute (aka on input) coordinate matching cp.
+func (cp CubePos) Abs() Pos {
+	return cp.Side.Abs(cp.RelPos)
+}
+
+func (cp CubePos) Password() int {
+	abs := cp.Abs()
+	return abs.Row*1000 + abs.Col*4 + int(cp.Heading)
+}
+
+var I_RE = regexp.MustCompile(`^([LR]?\d+)*$`)
+var STEP_RE = regexp.MustCompile(`([LR])?(\d+)`)
+
+// Reads the map (as absolute positions) and the instruction line.
+func Parse(r io.Reader) (input map[Pos]Content, instructions string, err error) {
+	s := bufio.NewScanner(r)
+	input = map[Pos]Content{}
+	row := 1
+	for s.Scan() && s.Text() != "" {
+		for col, c := range s.Text() {
+			switch c {
+			case ' ':
+			case '.':
+				input[Pos{row, col + 1}] = OPEN
+			case '#':
+				input[Pos{row, col + 1}] = WALL
+			default:
+				return nil, "", fmt.Errorf("bad input at %d,%d, unknown char '%c'", row, col+1, c)
+			}
+		}
+		row++
+	}
+	if !s.Scan() {
+		return nil, "", fmt.Errorf("couldn't read instruction line")
+	}
+	instructions = s.Text()
+	if !I_RE.MatchString(instructions) {
+		return nil, "", fmt.Errorf("bad instructions: %s", instructions)
+	}
+	return input, instructions, s.Err()
+}
+
+// Folds the 2D input into a cube. The input must be 6 square sides, of any
+// size, laid out as one of the 11 nets of a cube.
+func NewCube(input map[Pos]Content) (*Cube, error) {
+	size := 0
+	for size*size*6 < len(input) {
+		size++
+	}
+	if size == 0 || size*size*6 != len(input) {
+		return nil, fmt.Errorf("%d cells can't make the 6 square sides of a cube", len(input))
+	}
+
+	c := &Cube{Size: size, sides: map[Pos]*Side{}, facing: map[vec]*Side{}}
+	for p, what := range input {
+		sp := Pos{(p.Row - 1) / size, (p.Col - 1) / size}
+		s, found := c.sides[sp]
+		if !found {
+			s = &Side{SidePos: sp, Size: size, c: map[Pos]Content{}}
+			c.sides[sp] = s
+		}
+		s.c[Pos{p.Row - 1 - sp.Row*size, p.Col - 1 - sp.Col*size}] = what
+	}
+	if len(c.sides) != 6 {
+		return nil, fmt.Errorf("found %d sides of size %d, expected 6", len(c.sides), size)
+	}
+
+	first := c.Sides()[0]
+	first.normal, first.right, first.down = vec{0, 0, -1}, vec{1, 0, 0}, vec{0, 1, 0}
+	first.folded = true
+	c.Fold(first)
+	for _, s := range c.Sides() {
+		if !s.folded {
+			return nil, fmt.Errorf("%s is not joined to the rest of the net", s)
+		}
+		if other, taken := c.facing[s.normal]; taken {
+			return nil, fmt.Errorf("%s folds onto %s, not a cube net", s, other.SidePos)
+		}
+		c.facing[s.normal] = s
+	}
+	return c, nil
+}
+
+// The sides of the cube, in reading order of the input.
+func (c *Cube) Sides() []*Side {
+	rv := []*Side{}
+	for _, s := range c.sides {
+		rv = append(rv, s)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].SidePos.Row != rv[j].SidePos.Row {
+			return rv[i].SidePos.Row < rv[j].SidePos.Row
+		}
+		return rv[i].SidePos.Col < rv[j].SidePos.Col
+	})
+	return rv
+}
+
+// Folds each side joined to s in the input up against it, then carries on
+// from there. Folding up over an edge turns the side so it faces the way
+// that edge led, and the way that edge led now points back down.
+func (c *Cube) Fold(s *Side) {
+	for _, dir := range DIRECTIONS {
+		next, found := c.sides[s.SidePos.Step(dir)]
+		if !found || next.folded {
+			continue
+		}
+		next.normal, next.right, next.down = s.vec(dir), s.right, s.down
+		switch dir {
+		case D_RIGHT:
+			next.right = s.normal.neg()
+		case D_LEFT:
+			next.right = s.normal
+		case D_DOWN:
+			next.down = s.normal.neg()
+		case D_UP:
+			next.down = s.normal
+		}
+		next.folded = true
+		c.Fold(next)
+	}
+}
+
+// Returns where a step off the edge of the side from lands: on the side
+// facing the way we were heading, one cell in, heading away from from's
+// side.
+func (c *Cube) Wrap(from CubePos) CubePos {
+	s := from.Side
+	v := s.vec(from.Heading)
+	to := c.facing[v]
+	p := s.centre(from.RelPos).add(s.normal, -1).add(v, 1)
+	return CubePos{
+		Side:    to,
+		RelPos:  Pos{(p.dot(to.down) + c.Size - 1) / 2, (p.dot(to.right) + c.Size - 1) / 2},
+		Heading: to.heading(s.normal.neg()),
+	}
+}
+
+// Returns the position one step on from from, wrapping round the cube if
+// needed (but ignoring walls).
+func (c *Cube) Next(from CubePos) CubePos {
+	p := from.RelPos.Step(from.Heading)
+	if p.Row < 0 || p.Col < 0 || p.Row >= c.Size || p.Col >= c.Size {
+		return c.Wrap(from)
+	}
+	return CubePos{Side: from.Side, RelPos: p, Heading: from.Heading}
+}
+
+func (c *Cube) C(p CubePos) Content {
+	return p.Side.C(p.RelPos)
+}
+
+// Moves up to steps forward from from, stopping at any wall.
+func (c *Cube) Nav(from CubePos, steps int) CubePos {
+	at := from
+	for i := 0; i < steps; i++ {
+		next := c.Next(at)
+		if c.C(next) == WALL {
+			break
+		}
+		at = next
+	}
+	return at
+}
+
+// Returns the cube position of an absolute input position.
+func (c *Cube) At(abs Pos, heading Direction) (CubePos, error) {
+	sp := Pos{(abs.Row - 1) / c.Size, (abs.Col - 1) / c.Size}
+	s, found := c.sides[sp]
+	if !found || abs.Row < 1 || abs.Col < 1 {
+		return CubePos{}, fmt.Errorf("%s is not on the cube", abs)
+	}
+	return CubePos{Side: s, RelPos: Pos{abs.Row - 1 - sp.Row*c.Size, abs.Col - 1 - sp.Col*c.Size}, Heading: heading}, nil
+}
+
+// Returns the puzzle's starting position: the leftmost open cell of the top
+// row, facing right.
+func (c *Cube) Start() (CubePos, error) {
+	for _, s := range c.Sides() {
+		if s.SidePos.Row != c.Sides()[0].SidePos.Row {
+			break
+		}
+		for col := 0; col < c.Size; col++ {
+			if s.C(Pos{0, col}) == OPEN {
+				return CubePos{Side: s, RelPos: Pos{0, col}, Heading: D_RIGHT}, nil
+			}
+		}
+	}
+	return CubePos{}, fmt.Errorf("no open cell on the top row")
+}
+
+// Follows the instructions (e.g. 10R5L5) from from, returning where they end.
+func (c *Cube) Follow(from CubePos, instructions string) CubePos {
+	pos := from
+	for _, i := range STEP_RE.FindAllStringSubmatch(instructions, -1) {
+		if i[1] != "" {
+			pos.Heading = pos.Heading.Turn(i[1])
+		}
+		steps, _ := strconv.Atoi(i[2])
+		pos = c.Nav(pos, steps)
+	}
+	return pos
+}
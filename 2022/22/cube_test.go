@@ -0,0 +1,166 @@
+package day22
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sample(t *testing.T) {
+	f, err := os.Open("sample")
+	require.NoError(t, err)
+	defer f.Close()
+	input, instructions, err := Parse(f)
+	require.NoError(t, err)
+	assert.Equal(t, "10R5L5R10L4R5L5", instructions)
+
+	c, err := NewCube(input)
+	require.NoError(t, err)
+	assert.Equal(t, 4, c.Size)
+	assert.Equal(t, 6, len(c.Sides()))
+
+	start, err := c.Start()
+	require.NoError(t, err)
+	assert.Equal(t, Pos{1, 9}, start.Abs())
+
+	// The wraps the puzzle walks through in its example.
+	from, err := c.At(Pos{6, 12}, D_RIGHT)
+	require.NoError(t, err)
+	to := c.Wrap(from)
+	assert.Equal(t, Pos{9, 15}, to.Abs())
+	assert.Equal(t, D_DOWN, to.Heading)
+	from, err = c.At(Pos{12, 11}, D_DOWN)
+	require.NoError(t, err)
+	to = c.Wrap(from)
+	assert.Equal(t, Pos{8, 2}, to.Abs())
+	assert.Equal(t, D_UP, to.Heading)
+
+	end := c.Follow(start, instructions)
+	assert.Equal(t, Pos{5, 7}, end.Abs())
+	assert.Equal(t, D_UP, end.Heading)
+	assert.Equal(t, 5031, end.Password())
+}
+
+// The 11 nets of a cube, each side a single character.
+var nets = []string{
+	"x...\nxxxx\nx...",
+	"x...\nxxxx\n.x..",
+	"x...\nxxxx\n..x.",
+	"x...\nxxxx\n...x",
+	".x..\nxxxx\n.x..",
+	".x..\nxxxx\n..x.",
+	"xx..\n.xxx\n.x..",
+	"xx..\n.xxx\n..x.",
+	"xx..\n.xxx\n...x",
+	"xx..\n.xx.\n..xx",
+	"xxx..\n..xxx",
+}
+
+// Returns the sides of net, rotated and flipped by one of the 8 symmetries
+// of the square.
+func transform(net string, sym int) []Pos {
+	sides := []Pos{}
+	for row, l := range strings.Split(net, "\n") {
+		for col, ch := range l {
+			if ch != 'x' {
+				continue
+			}
+			r, c := row, col
+			if sym&1 != 0 {
+				c = -c
+			}
+			if sym&2 != 0 {
+				r = -r
+			}
+			if sym&4 != 0 {
+				r, c = c, r
+			}
+			sides = append(sides, Pos{r, c})
+		}
+	}
+	minR, minC := sides[0].Row, sides[0].Col
+	for _, p := range sides {
+		minR, minC = min(minR, p.Row), min(minC, p.Col)
+	}
+	for n := range sides {
+		sides[n] = Pos{sides[n].Row - minR, sides[n].Col - minC}
+	}
+	return sides
+}
+
+// Returns an open input with size x size sides at the given side positions.
+func netInput(sides []Pos, size int) map[Pos]Content {
+	input := map[Pos]Content{}
+	for _, sp := range sides {
+		for r := 0; r < size; r++ {
+			for c := 0; c < size; c++ {
+				input[Pos{sp.Row*size + r + 1, sp.Col*size + c + 1}] = OPEN
+			}
+		}
+	}
+	return input
+}
+
+func Test_Nets(t *testing.T) {
+	for n, net := range nets {
+		for sym := 0; sym < 8; sym++ {
+			for size := 1; size <= 4; size++ {
+				t.Run(fmt.Sprintf("net%d/sym%d/size%d", n, sym, size), func(t *testing.T) {
+					c, err := NewCube(netInput(transform(net, sym), size))
+					require.NoError(t, err)
+					walkEdges(t, c)
+				})
+			}
+		}
+	}
+}
+
+// Steps off every edge cell of every side, checks stepping straight back
+// returns to the start, and walks once round the cube from every cell.
+func walkEdges(t *testing.T, c *Cube) {
+	for _, s := range c.Sides() {
+		for r := 0; r < c.Size; r++ {
+			for col := 0; col < c.Size; col++ {
+				for _, d := range DIRECTIONS {
+					start := CubePos{Side: s, RelPos: Pos{r, col}, Heading: d}
+					if p := start.RelPos.Step(d); p.Row < 0 || p.Col < 0 || p.Row >= c.Size || p.Col >= c.Size {
+						there := c.Wrap(start)
+						require.NotEqual(t, s, there.Side, "%s wrapped onto its own side", start)
+						there.Heading = there.Heading.Reverse()
+						back := c.Wrap(there)
+						back.Heading = back.Heading.Reverse()
+						require.Equal(t, start, back, "%s wrapped to %s", start, there)
+					}
+
+					at, sides := start, map[*Side]bool{}
+					for i := 0; i < 4*c.Size; i++ {
+						sides[at.Side] = true
+						at = c.Nav(at, 1)
+					}
+					require.Equal(t, start, at, "walking round from %s", start)
+					require.Equal(t, 4, len(sides), "walking round from %s", start)
+				}
+			}
+		}
+	}
+}
+
+func Test_BadNets(t *testing.T) {
+	for _, net := range []string{
+		"xxx\nxxx",       // folds two sides onto one
+		"xxxxxx",         // likewise
+		"xx.\n...\nxxxx", // not joined up
+		"xxxx\nxxxx",     // too many sides
+	} {
+		_, err := NewCube(netInput(transform(net, 0), 2))
+		assert.Error(t, err, net)
+	}
+	input := netInput(transform(nets[0], 0), 2)
+	delete(input, Pos{1, 1})
+	_, err := NewCube(input)
+	assert.Error(t, err)
+}
@@ -1,5 +1,7 @@
 // Copyright (C) 2022 Matt Brown
 
+//go:build ignore
+
 // Advent of Code 2022 - Day 24, Puzzle 1.
 // Blizzard Basin. Another search...
 
@@ -13,6 +15,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/mattbnz/aoc/lib/search"
 )
 
 func Abs(x int) int {
@@ -342,35 +346,36 @@ func (g *Grid) DistFrom(min int, p2 Pos) int {
 	return Abs(p2.row-p.row) + Abs(p2.col-p.col)
 }
 
-// Returns possible positions for the expedition in this minute
-func (g *Grid) Moves() []Pos {
-	c := g.FindE(g.minute - 1)
-	rv := []Pos{}
-	for _, p := range []Pos{c, {c.row - 1, c.col}, {c.row + 1, c.col}, {c.row, c.col - 1}, {c.row, c.col + 1}} {
-		t := g.C(p, -1)
-		if t.exists && t.Open() {
-			rv = append(rv, p)
-		}
+// Where the expedition is, and when.
+type State struct {
+	p      Pos
+	minute int
+}
+
+func (s State) String() string {
+	return fmt.Sprintf("%s@%d", s.p, s.minute)
+}
+
+// Returns whether p is free of walls and blizzards in minute min, moving
+// the blizzards on as far as needed.
+func (g *Grid) OpenAt(p Pos, min int) bool {
+	for g.minute < min {
+		g.Inc()
 	}
-	return rv
+	c := g.C(p, min)
+	return c.exists && c.Open()
 }
 
-func (g *Grid) Speed() float64 {
-	worst := 0
-	best := g.eDist
-	for m := g.minute; m > Max(0, g.minute-10); m-- {
-		d := g.DistFrom(m, g.exit)
-		if d < best {
-			best = d
-		}
-		if d > worst {
-			worst = d
+// Returns the possible states for the expedition a minute after s (moving,
+// or waiting where it is).
+func (g *Grid) Moves(s State) (rv []State) {
+	c := s.p
+	for _, p := range []Pos{c, {c.row - 1, c.col}, {c.row + 1, c.col}, {c.row, c.col - 1}, {c.row, c.col + 1}} {
+		if g.OpenAt(p, s.minute+1) {
+			rv = append(rv, State{p, s.minute + 1})
 		}
 	}
-	if best < g.bestDist {
-		return 0.0
-	}
-	return float64(worst-best) / 10.0
+	return
 }
 
 func NewGrid() Grid {
@@ -380,61 +385,6 @@ func NewGrid() Grid {
 	return g
 }
 
-// A priority Queue entry.
-type PQE struct {
-	grid Grid
-	prio float64
-}
-
-// Priority Queue
-type PQ struct {
-	Q []PQE
-
-	lastSpeeds []float64
-	start      Pos
-}
-
-func (q *PQ) Add(g Grid) PQE {
-	qe := PQE{grid: g}
-	dist := float64(g.DistFrom(NOW, q.start))
-	qe.prio = dist + g.Speed()
-	if q.lastSpeeds == nil {
-		q.lastSpeeds = make([]float64, 10)
-	}
-	q.lastSpeeds = append(q.lastSpeeds[1:], qe.prio)
-	if len(q.Q) == 0 {
-		q.Q = append(q.Q, qe)
-		return qe
-	}
-	if qe.prio > q.Q[0].prio {
-		q.Q = append([]PQE{qe}, q.Q...)
-		return qe
-	}
-	for n, t := range q.Q {
-		if qe.prio > t.prio {
-			q.Q = append(q.Q[:n+1], q.Q[n:]...)
-			q.Q[n] = qe
-			return qe
-		}
-	}
-	q.Q = append(q.Q, qe)
-	return qe
-}
-
-func (q *PQ) Pop() PQE {
-	e := q.Q[0]
-	q.Q = q.Q[1:]
-	return e
-}
-
-func (q *PQ) AvgSpeed() float64 {
-	sum := 0.0
-	for _, e := range q.lastSpeeds {
-		sum += e
-	}
-	return sum / float64(len(q.lastSpeeds))
-}
-
 func main() {
 	s := bufio.NewScanner(os.Stdin)
 
@@ -472,81 +422,16 @@ func main() {
 	startGrid.bestDist = exitDist
 	startGrid.bestMinute = 0
 
-	q := PQ{start: start}
-	q.Add(startGrid)
-	seen := map[string]bool{}
-	var best *Grid
-	bestDist := exitDist
-	for len(q.Q) > 0 {
-		qe := q.Pop()
-		g := qe.grid
-		k := g.Key()
-		if _, done := seen[k]; done {
-			fmt.Printf("Skipping %s - already done\n", g)
-			continue
-		}
-		prefix := g.Prefix()
-		seen[k] = true
-		if best != nil {
-			fastest := g.minute + g.eDist
-			if g.minute > best.minute {
-				fmt.Printf("%s Giving up > best minute %d\n", prefix, best.minute)
-				continue
-			} else if fastest > best.minute {
-				fmt.Printf("%s Giving up, best possible exit at @%d > %d\n", prefix, fastest, best.minute)
-				continue
-			}
-		}
-		if g.bestDist < bestDist {
-			bestDist = g.bestDist
-		} else if g.eDist > int(float64(bestDist)*0.50) {
-			//g.Print(prefix, "Giving up - not making progress")
-			fmt.Println(prefix, "Giving up - not making progress")
-			//continue
-		} else if g.eDist > g.bestDist && (g.minute-g.bestMinute) > (g.eDist-g.bestDist) {
-			fmt.Printf("%s Giving up, gone off the rails (%d@%d vs %d@%d)\n", prefix,
-				g.eDist, g.minute, g.bestDist, g.bestMinute)
-			//continue
-		}
-		avg := q.AvgSpeed()
-		if qe.prio < avg/2 {
-			//g.Print(prefix, "Giving up, slow")
-			fmt.Println(prefix, "Giving up, slow")
-			//continue
-		}
-		fmt.Println(prefix, fmt.Sprintf("dist=%d, best=%d, spd=%.2f (avg %.2f)",
-			g.eDist, g.bestDist, qe.prio, avg), g.Path(15))
-		at := g.FindE(NOW)
-		fmt.Println(at)
-		g.Inc()
-		moves := g.Moves()
-		for _, m := range moves {
-			if m == exit {
-				fmt.Printf("%s Reached exit at minute %d!\n", prefix, g.minute)
-				if best == nil || best.minute > g.minute {
-					fmt.Println(" - and it's our new best!", g.minute)
-					best = &g
-				}
-				g.Print(prefix, "Exit to "+m.String())
-				continue
-			}
-			nextGrid := g.Copy()
-			nc := g.C(m, NOW)
-			nc.E = true
-			nextGrid.SetC(m, NOW, nc)
-			qe := q.Add(nextGrid)
-			//nextGrid.Print(prefix,
-			//	fmt.Sprintf("%s move to %s  (%s), ql=%d", at, m,
-			//		nextGrid.Key(), len(q.Q)))
-			fmt.Println(prefix,
-				fmt.Sprintf("%s move to %s (%s, spd=%.2f), ql=%d",
-					at, m, nextGrid.Key(), qe.prio, len(q.Q)))
-		}
+	g := search.Graph[State]{
+		Neighbors: startGrid.Moves,
+		Heuristic: func(s State) int {
+			return Abs(exit.row-s.p.row) + Abs(exit.col-s.p.col)
+		},
 	}
-
-	if best != nil {
-		fmt.Printf("Reached exit in %d minutes\n", best.minute)
-	} else {
+	r := g.Search(func(s State) bool { return s.p == exit }, State{start, 0})
+	if !r.Found {
 		log.Fatal("Did not reach the exit!")
 	}
+	fmt.Printf("Explored %d states\n", r.Explored)
+	fmt.Printf("Reached exit in %d minutes\n", r.Distance)
 }
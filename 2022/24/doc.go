@@ -0,0 +1,7 @@
+// Advent of Code 2022 - Day 24.
+// Blizzard Basin.
+//
+// Each puzzle is a standalone program, built only when named explicitly:
+//
+//	go run blizzard.go < input
+package day24
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 type Corner int
@@ -60,7 +62,6 @@ type CornerState struct {
 
 type PipeCell struct {
 	BaseCell
-	id Pos
 
 	IsStart bool // marker for start section.
 	// Distance from start in pipe (if this is a connected section)
@@ -72,15 +73,14 @@ type PipeCell struct {
 	WetCorner map[Corner]CornerState
 }
 
-var _ Cell = &PipeCell{}
+var _ Cell[*PipeCell] = &PipeCell{}
 
-func (c *PipeCell) New(s string, p Pos) Cell {
+func (c *PipeCell) New(s string, p Pos) *PipeCell {
 	nc := &PipeCell{
-		id:        p,
+		BaseCell:  BaseCell{ID: p, Symbol: s},
 		Connects:  map[CardinalDirection]bool{},
 		WetCorner: map[Corner]CornerState{},
 	}
-	nc.Symbol = s
 	switch nc.Symbol {
 	case "|":
 		nc.Connects[NORTH] = true
@@ -128,7 +128,7 @@ func (c *PipeCell) Travel(from CardinalDirection) (to CardinalDirection, err err
 }
 
 type Maze struct {
-	g     Grid
+	g     Grid[*PipeCell]
 	depth int
 }
 
@@ -145,8 +145,7 @@ func NewMaze(filename string) (Maze, error) {
 
 func (m Maze) FindStart() (p Pos, c *PipeCell, err error) {
 	err = fmt.Errorf("start marker not found")
-	m.g.Each(func(iPos Pos, iCell Cell) bool {
-		pCell := iCell.(*PipeCell)
+	m.g.Each(func(iPos Pos, pCell *PipeCell) bool {
 		if pCell.IsStart {
 			p = iPos
 			c = pCell
@@ -159,8 +158,7 @@ func (m Maze) FindStart() (p Pos, c *PipeCell, err error) {
 }
 
 func (m Maze) C(p Pos) *PipeCell {
-	c := m.g.C(p)
-	return c.(*PipeCell)
+	return m.g.C(p)
 }
 
 func (m Maze) LongestPath() (steps int, err error) {
@@ -172,11 +170,10 @@ func (m Maze) LongestPath() (steps int, err error) {
 	var moveA, moveB CardinalDirection
 	var posA, posB Pos
 	for _, dir := range CardinalDirections {
-		otherPos, otherCell, found := m.g.Next(startPos, dir)
+		otherPos, oCell, found := m.g.Next(startPos, dir)
 		if !found {
 			continue
 		}
-		oCell := otherCell.(*PipeCell)
 		if oCell.Connects[dir.Opposite()] {
 			startCell.Connects[dir] = true
 			glog.Infof("Found connection %s to %s from starting cell %s", dir, otherPos, startCell)
@@ -237,16 +234,14 @@ func (m Maze) CountEnclosed() (enclosed int, err error) {
 		return
 	}
 
-	/*m.g.Each(func(iPos Pos, iCell Cell) bool {
-		c := iCell.(*PipeCell)
+	/*m.g.Each(func(iPos Pos, c *PipeCell) bool {
 		c.Flood(&m, TL)
 		return true
 	})*/
-	c := m.C(Pos{1, 1})
+	c := m.C(Pos{Row: 1, Col: 1})
 	c.Flood(&m, TL)
 
-	m.g.Each(func(iPos Pos, iCell Cell) bool {
-		c := iCell.(*PipeCell)
+	m.g.Each(func(iPos Pos, c *PipeCell) bool {
 		if c.Distance == 0 && !c.IsStart && !c.HasWetCorner() {
 			enclosed++
 		}
@@ -258,7 +253,7 @@ func (m Maze) CountEnclosed() (enclosed int, err error) {
 func (c *PipeCell) HasWetCorner() bool {
 	for corner, state := range c.WetCorner {
 		if state.wet {
-			glog.V(2).Infof("%s has wet corner %s", c.id, corner)
+			glog.V(2).Infof("%s has wet corner %s", c.ID, corner)
 			return true
 		}
 	}
@@ -267,17 +262,17 @@ func (c *PipeCell) HasWetCorner() bool {
 
 // Marks corners in specified directions as wet
 func (c *PipeCell) FloodNeighbours(m *Maze, from Corner, dirA, dirB CardinalDirection) {
-	_, aCell, aOK := m.g.Next(c.id, dirA)
+	_, aCell, aOK := m.g.Next(c.ID, dirA)
 	if aOK {
-		aCell.(*PipeCell).Flood(m, from.Neighbour(dirA))
+		aCell.Flood(m, from.Neighbour(dirA))
 	}
-	bPos, bCell, bOK := m.g.Next(c.id, dirB)
+	bPos, bCell, bOK := m.g.Next(c.ID, dirB)
 	if bOK {
 		bCorner := from.Neighbour(dirB)
-		bCell.(*PipeCell).Flood(m, bCorner)
+		bCell.Flood(m, bCorner)
 		_, cCell, cOK := m.g.Next(bPos, dirA)
 		if cOK {
-			cCell.(*PipeCell).Flood(m, bCorner.Neighbour(dirA))
+			cCell.Flood(m, bCorner.Neighbour(dirA))
 		}
 	}
 }
@@ -303,7 +298,7 @@ func (c *PipeCell) Flood(m *Maze, from Corner) {
 		return
 	}
 	c.WetCorner[from] = CornerState{visited: true, wet: true}
-	glog.V(2).Infof("%s%s.%s is now wet", m.Prefix(), c.id, from)
+	glog.V(2).Infof("%s%s.%s is now wet", m.Prefix(), c.ID, from)
 	m.Depth(1)
 	defer func() { m.Depth(-1) }()
 	// Neighbours
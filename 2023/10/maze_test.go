@@ -1,6 +1,8 @@
 package day10
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,6 +60,53 @@ func Test_Sample6(t *testing.T) {
 	assert.Equal(t, 10, c)
 }
 
+// The shoelace count agrees with flooding on every sample.
+func Test_Pick(t *testing.T) {
+	for n := 1; n <= 6; n++ {
+		filename := "sample"
+		if n > 1 {
+			filename = fmt.Sprintf("sample%d", n)
+		}
+		flood, err := NewMaze(filename)
+		require.NoError(t, err)
+		want, err := flood.CountEnclosed()
+		require.NoError(t, err)
+
+		maze, err := NewMaze(filename)
+		require.NoError(t, err)
+		got, err := maze.CountEnclosedPick()
+		require.NoError(t, err)
+		assert.Equal(t, want, got, filename)
+
+		loop, err := maze.Loop()
+		require.NoError(t, err)
+		path, err := maze.LongestPath()
+		require.NoError(t, err)
+		assert.Equal(t, path, len(loop)/2, filename)
+
+		var sb strings.Builder
+		require.NoError(t, maze.WriteEnclosed(&sb))
+		assert.Equal(t, want, strings.Count(sb.String(), "I"), filename)
+	}
+}
+
+func Test_WriteEnclosed(t *testing.T) {
+	maze, err := NewMaze("sample4")
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, maze.WriteEnclosed(&sb))
+	assert.Equal(t, `OOOOOOOOOO
+OS------7O
+O|F----7|O
+O||OOOO||O
+O||OOOO||O
+O|L-7F-J|O
+O|II||II|O
+OL--JL--JO
+OOOOOOOOOO
+`, sb.String())
+}
+
 func Test_Part1(t *testing.T) {
 	maze, err := NewMaze("input")
 	require.NoError(t, err)
@@ -0,0 +1,129 @@
+package day10
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
+)
+
+// Returns the tiles of the loop through the start, in the order a walk
+// around it from the start visits them.
+func (m Maze) Loop() ([]Pos, error) {
+	start, _, err := m.FindStart()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range CardinalDirections {
+		loop, err := m.follow(start, dir)
+		if err == nil {
+			return loop, nil
+		}
+		glog.V(1).Infof("Heading %s from start at %s: %v", dir, start, err)
+	}
+	return nil, fmt.Errorf("no loop through the start at %s", start)
+}
+
+// Follows the pipes from start, leaving with heading, until they come back
+// to start.
+func (m Maze) follow(start Pos, heading CardinalDirection) (loop []Pos, err error) {
+	loop = []Pos{start}
+	p := start
+	for {
+		np, c, found := m.g.Next(p, heading)
+		if !found {
+			return nil, fmt.Errorf("left the grid going %s from %s", heading, p)
+		}
+		if np == start {
+			return loop, nil
+		}
+		heading, err = c.Travel(heading)
+		if err != nil {
+			return nil, err
+		}
+		p = np
+		loop = append(loop, p)
+	}
+}
+
+// Returns the tiles where the loop turns, which are all the shoelace
+// formula needs.
+func vertices(loop []Pos) (rv []Pos) {
+	for n, p := range loop {
+		prev, next := loop[(n+len(loop)-1)%len(loop)], loop[(n+1)%len(loop)]
+		if prev.Row != next.Row && prev.Col != next.Col {
+			rv = append(rv, p)
+		}
+	}
+	return
+}
+
+// Counts the tiles enclosed by the loop without flooding: the shoelace
+// formula gives the loop's area A (through the middle of its tiles), and
+// Pick's theorem, A = i + b/2 - 1, gives the i tiles inside from the b
+// tiles on the loop.
+func (m Maze) CountEnclosedPick() (int, error) {
+	loop, err := m.Loop()
+	if err != nil {
+		return 0, err
+	}
+	vs := vertices(loop)
+	twiceArea := 0
+	for n, a := range vs {
+		b := vs[(n+1)%len(vs)]
+		twiceArea += a.Col*b.Row - b.Col*a.Row
+	}
+	if twiceArea < 0 {
+		twiceArea = -twiceArea
+	}
+	glog.V(1).Infof("Loop of %d tiles with %d turns has area %d/2", len(loop), len(vs), twiceArea)
+	return (twiceArea-len(loop))/2 + 1, nil
+}
+
+// Writes the maze the way the puzzle draws it: the loop's pipes as they
+// are, and every other tile as I if the loop encloses it or O if not.
+func (m Maze) WriteEnclosed(w io.Writer) error {
+	loop, err := m.Loop()
+	if err != nil {
+		return err
+	}
+	// Which tiles are on the loop, and whether each joins the tile north of
+	// it, as that's when a scan along the row crosses it.
+	onLoop := map[Pos]bool{}
+	for n, p := range loop {
+		prev, next := loop[(n+len(loop)-1)%len(loop)], loop[(n+1)%len(loop)]
+		north := p.Move(NORTH)
+		onLoop[p] = prev == north || next == north
+	}
+
+	bw := bufio.NewWriter(w)
+	for r := 1; r <= m.g.MaxRow(); r++ {
+		inside := false
+		for c := 1; c <= m.g.MaxCol(); c++ {
+			p := Pos{Row: r, Col: c}
+			crosses, found := onLoop[p]
+			switch {
+			case found:
+				bw.WriteString(m.C(p).Symbol)
+				if crosses {
+					inside = !inside
+				}
+			case inside:
+				bw.WriteString("I")
+			default:
+				bw.WriteString("O")
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// Prints the maze with the enclosed tiles marked.
+func (m Maze) PrintEnclosed() error {
+	return m.WriteEnclosed(os.Stdout)
+}
@@ -0,0 +1,20 @@
+package day10
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 10, 1, solver.Func(func(filename string) (any, error) {
+		maze, err := NewMaze(filename)
+		if err != nil {
+			return nil, err
+		}
+		return maze.LongestPath()
+	}))
+	solver.Register(2023, 10, 2, solver.Func(func(filename string) (any, error) {
+		maze, err := NewMaze(filename)
+		if err != nil {
+			return nil, err
+		}
+		return maze.CountEnclosed()
+	}))
+}
@@ -1,19 +1,26 @@
 package day11
 
 import (
+	"fmt"
+	"math/big"
 	"os"
+	"slices"
 
 	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
+// Space is the galaxies in an image, kept as positions rather than a grid
+// so that expanding it by any factor is arithmetic on the coordinates.
 type Space struct {
-	g Grid
-
-	Galaxies map[int]Pos
+	// In the order the puzzle numbers them, row by row.
+	Galaxies []Pos
 
-	ExpandedRows []int
-	ExpandedCols []int
-	ExpandedBy   int
+	// emptyRows[r] is how many rows before row r have no galaxy in, for
+	// r up to one past the last row (likewise emptyCols).
+	emptyRows []int64
+	emptyCols []int64
 }
 
 func NewSpace(filename string) (Space, error) {
@@ -23,151 +30,93 @@ func NewSpace(filename string) (Space, error) {
 	}
 	defer f.Close()
 
-	space := Space{g: NewGrid[BaseCell](f), Galaxies: map[int]Pos{}}
-	return space, nil
-}
-
-func (s *Space) Expand() {
-	row := 1
-	for {
-		if row > s.g.maxrow {
-			break
-		}
-		hasGalaxy := false
-		for col := 1; col <= s.g.maxcol; col++ {
-			if s.g.C(Pos{row, col}).(BaseCell).Symbol != "." {
-				hasGalaxy = true
-				break
-			}
-		}
-		if hasGalaxy {
-			row++
-			continue
-		}
-		s.g.DupRow(row)
-		row += 2
-	}
-
-	col := 1
-	for {
-		if col > s.g.maxcol {
-			break
+	g := NewGrid[BaseCell](f)
+	s := Space{}
+	hasRow := make([]bool, g.MaxRow()+1)
+	hasCol := make([]bool, g.MaxCol()+1)
+	g.Each(func(p Pos, c BaseCell) bool {
+		if c.Symbol == "#" {
+			s.Galaxies = append(s.Galaxies, p)
+			hasRow[p.Row], hasCol[p.Col] = true, true
 		}
-		hasGalaxy := false
-		for row := 1; row <= s.g.maxrow; row++ {
-			if s.g.C(Pos{row, col}).(BaseCell).Symbol != "." {
-				hasGalaxy = true
-				break
-			}
-		}
-		if hasGalaxy {
-			col++
-			continue
-		}
-		s.g.DupCol(col)
-		col += 2
-	}
+		return true
+	})
+	s.emptyRows = emptyBefore(hasRow)
+	s.emptyCols = emptyBefore(hasCol)
+	glog.Infof("Found %d galaxies", len(s.Galaxies))
+	return s, nil
 }
 
-func (s *Space) ExpandBy(by int) {
-	for row := 1; row <= s.g.maxrow; row++ {
-		hasGalaxy := false
-		for col := 1; col <= s.g.maxcol; col++ {
-			if s.g.C(Pos{row, col}).(BaseCell).Symbol != "." {
-				hasGalaxy = true
-				break
-			}
+// Returns the prefix sums of the lines (numbered from 1) without galaxies.
+func emptyBefore(has []bool) []int64 {
+	rv := make([]int64, len(has)+1)
+	for n := 1; n < len(has); n++ {
+		rv[n+1] = rv[n]
+		if !has[n] {
+			rv[n+1]++
 		}
-		if hasGalaxy {
-			continue
-		}
-		s.ExpandedRows = append(s.ExpandedRows, row)
 	}
+	return rv
+}
 
-	for col := 1; col <= s.g.maxcol; col++ {
-		hasGalaxy := false
-		for row := 1; row <= s.g.maxrow; row++ {
-			if s.g.C(Pos{row, col}).(BaseCell).Symbol != "." {
-				hasGalaxy = true
-				break
-			}
-		}
-		if hasGalaxy {
-			continue
-		}
-		s.ExpandedCols = append(s.ExpandedCols, col)
-	}
-	s.ExpandedBy = by
+// Returns where row r ends up once every empty row before it has become
+// factor rows.
+func (s Space) ExpandRow(r int, factor int64) int64 {
+	return int64(r) + s.emptyRows[r]*(factor-1)
 }
 
-func (s *Space) FindGalaxies() {
-	gN := 1
-	s.g.Each(func(p Pos, c Cell) bool {
-		if c.(BaseCell).Symbol == "#" {
-			ep := s.ExpandPos(p)
-			s.Galaxies[gN] = ep
-			glog.V(1).Infof("Galaxy %d at %s (from %s)", gN, ep, p)
-			gN++
-		}
-		return true
-	})
-	glog.Infof("Found %d galaxies", len(s.Galaxies))
+// Returns where col c ends up once every empty column before it has become
+// factor columns.
+func (s Space) ExpandCol(c int, factor int64) int64 {
+	return int64(c) + s.emptyCols[c]*(factor-1)
 }
 
-// returns the virtual (expanded pos p)
-func (s *Space) ExpandPos(p Pos) (ep Pos) {
-	if s.ExpandedBy == 0 {
-		return p
-	}
-	rEidx := 0
-	ep.row = 1
-	for row := 1; row < p.row; row++ {
-		if rEidx < len(s.ExpandedRows) && s.ExpandedRows[rEidx] == row {
-			ep.row += s.ExpandedBy - 1
-			rEidx++
-		}
-		ep.row++
-	}
+// Returns the expanded row and column of p.
+func (s Space) ExpandPos(p Pos, factor int64) (row, col int64) {
+	return s.ExpandRow(p.Row, factor), s.ExpandCol(p.Col, factor)
+}
 
-	cEidx := 0
-	ep.col = 1
-	for col := 1; col < p.col; col++ {
-		if cEidx < len(s.ExpandedCols) && s.ExpandedCols[cEidx] == col {
-			ep.col += s.ExpandedBy - 1
-			cEidx++
-		}
-		ep.col++
-	}
-	return
+// The shortest path between a and b (as positions in the unexpanded image)
+// once space is expanded.
+func (s Space) PathLength(a, b Pos, factor int64) int64 {
+	aRow, aCol := s.ExpandPos(a, factor)
+	bRow, bCol := s.ExpandPos(b, factor)
+	return abs64(aRow-bRow) + abs64(aCol-bCol)
 }
 
-func (s *Space) PathLength(a, b Pos) int {
-	return Abs(a.row-b.row) + Abs(a.col-b.col)
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
 }
 
-type gKey struct {
-	a, b int
+// Returns the sum of |a-b| over every pair of values.
+func pairDistances(values []int64) *big.Int {
+	slices.Sort(values)
+	// Once sorted, the nth value is after n others and before the rest, so
+	// it is added n times and subtracted len-1-n times.
+	sum := new(big.Int)
+	for n, v := range values {
+		times := big.NewInt(int64(2*n - (len(values) - 1)))
+		sum.Add(sum, times.Mul(times, big.NewInt(v)))
+	}
+	return sum
 }
 
-func (s *Space) PathSum(expansionFactor int) (rv int) {
-	if expansionFactor == 2 {
-		s.Expand()
-	} else {
-		s.ExpandBy(expansionFactor)
+// The sum of the shortest paths between every pair of galaxies, once every
+// empty row and column has become factor of them. Paths only go along rows
+// and columns, so this is each axis' pairwise distances summed separately.
+func (s Space) PathSum(factor int64) (*big.Int, error) {
+	if factor < 1 {
+		return nil, fmt.Errorf("expansion factor %d is less than 1", factor)
 	}
-	s.FindGalaxies()
-	cache := map[gKey]int{}
-	for gA, aPos := range s.Galaxies {
-		for gB, bPos := range s.Galaxies {
-			key := gKey{Min(gA, gB), Max(gA, gB)}
-			if _, done := cache[key]; done {
-				continue
-			}
-			length := s.PathLength(aPos, bPos)
-			rv += length
-			cache[key] = length
-			glog.V(1).Infof("Shortest path from %d@%s to %d@%s is %d", gA, aPos, gB, bPos, length)
-		}
+	rows := make([]int64, len(s.Galaxies))
+	cols := make([]int64, len(s.Galaxies))
+	for n, p := range s.Galaxies {
+		rows[n], cols[n] = s.ExpandPos(p, factor)
+		glog.V(1).Infof("Galaxy %d at %d,%d (from %s)", n+1, rows[n], cols[n], p)
 	}
-	return
+	sum := pairDistances(rows)
+	return sum.Add(sum, pairDistances(cols)), nil
 }
@@ -1,13 +1,23 @@
 package day11
 
 import (
+	"math/big"
+	"math/rand"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
+func pathSum(t *testing.T, space Space, factor int64) string {
+	sum, err := space.PathSum(factor)
+	require.NoError(t, err)
+	return sum.String()
+}
+
 func Test_Expansion(t *testing.T) {
 	space, err := NewSpace("sample")
 	require.NoError(t, err)
@@ -17,50 +27,79 @@ func Test_Expansion(t *testing.T) {
 	defer r.Close()
 	expected := NewGrid[BaseCell](r)
 
-	space.Expand()
-	if !assert.True(t, expected.Equal(space.g)) {
-		t.Log("Got")
-		space.g.PrintNumbered()
-		t.Log("Expected")
-		expected.PrintNumbered()
-	}
+	n := 0
+	expected.Each(func(p Pos, c BaseCell) bool {
+		if c.Symbol == "#" {
+			row, col := space.ExpandPos(space.Galaxies[n], 2)
+			assert.Equal(t, p, Pos{Row: int(row), Col: int(col)}, "galaxy %d", n+1)
+			n++
+		}
+		return true
+	})
+	assert.Equal(t, len(space.Galaxies), n)
 }
 
 func Test_Sample(t *testing.T) {
 	space, err := NewSpace("sample")
 	require.NoError(t, err)
-	assert.Equal(t, 374, space.PathSum(2))
+	assert.Equal(t, "374", pathSum(t, space, 2))
 
 	assert.Equal(t, 9, len(space.Galaxies))
-	space.g.PrintNumbered()
 
-	assert.Equal(t, 9, space.PathLength(space.Galaxies[5], space.Galaxies[9]))
-	assert.Equal(t, 15, space.PathLength(space.Galaxies[1], space.Galaxies[7]))
-	assert.Equal(t, 17, space.PathLength(space.Galaxies[3], space.Galaxies[6]))
-	assert.Equal(t, 5, space.PathLength(space.Galaxies[8], space.Galaxies[9]))
+	assert.Equal(t, int64(9), space.PathLength(space.Galaxies[4], space.Galaxies[8], 2))
+	assert.Equal(t, int64(15), space.PathLength(space.Galaxies[0], space.Galaxies[6], 2))
+	assert.Equal(t, int64(17), space.PathLength(space.Galaxies[2], space.Galaxies[5], 2))
+	assert.Equal(t, int64(5), space.PathLength(space.Galaxies[7], space.Galaxies[8], 2))
+
+	_, err = space.PathSum(0)
+	assert.Error(t, err)
 }
 
 func Test_Part1(t *testing.T) {
 	space, err := NewSpace("input")
 	require.NoError(t, err)
 
-	t.Logf("Path sum is %d", space.PathSum(2))
+	t.Logf("Path sum is %s", pathSum(t, space, 2))
 }
 
 func Test_Sample_10(t *testing.T) {
 	space, err := NewSpace("sample")
 	require.NoError(t, err)
-	assert.Equal(t, 1030, space.PathSum(10))
+	assert.Equal(t, "1030", pathSum(t, space, 10))
 }
 func Test_Sample_100(t *testing.T) {
 	space, err := NewSpace("sample")
 	require.NoError(t, err)
-	assert.Equal(t, 8410, space.PathSum(100))
+	assert.Equal(t, "8410", pathSum(t, space, 100))
+}
+
+// The sorted sum agrees with adding up every pair, even with factors whose
+// sum is past int64.
+func Test_PathSumPairs(t *testing.T) {
+	space, err := NewSpace("sample")
+	require.NoError(t, err)
+	r := rand.New(rand.NewSource(1))
+	for _, factor := range []int64{1, 2, 1000000, 1000000000, r.Int63n(1000000000) + 1} {
+		want := new(big.Int)
+		for a := range space.Galaxies {
+			for b := a + 1; b < len(space.Galaxies); b++ {
+				want.Add(want, big.NewInt(space.PathLength(space.Galaxies[a], space.Galaxies[b], factor)))
+			}
+		}
+		assert.Equal(t, want.String(), pathSum(t, space, factor), "factor %d", factor)
+	}
+
+	// Far enough apart that the sum needs more than 64 bits.
+	space = Space{emptyRows: make([]int64, 3), emptyCols: []int64{0, 0, 0, 1 << 20}}
+	for n := 0; n < 1000; n++ {
+		space.Galaxies = append(space.Galaxies, Pos{Row: 1, Col: 1 + 2*(n%2)})
+	}
+	assert.Equal(t, "262143999737856500000", pathSum(t, space, 1000000000))
 }
 
 func Test_Part2(t *testing.T) {
 	space, err := NewSpace("input")
 	require.NoError(t, err)
 
-	t.Logf("Path sum is %d", space.PathSum(1000000))
+	t.Logf("Path sum is %s", pathSum(t, space, 1000000))
 }
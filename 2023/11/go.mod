@@ -0,0 +1,19 @@
+module day11
+
+go 1.21.1
+
+require (
+	github.com/golang/glog v1.2.0
+	github.com/mattbnz/aoc/lib v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/mattbnz/aoc/lib => ../../lib
@@ -0,0 +1,20 @@
+package day11
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 11, 1, solver.Func(func(filename string) (any, error) {
+		space, err := NewSpace(filename)
+		if err != nil {
+			return nil, err
+		}
+		return space.PathSum(2)
+	}))
+	solver.Register(2023, 11, 2, solver.Func(func(filename string) (any, error) {
+		space, err := NewSpace(filename)
+		if err != nil {
+			return nil, err
+		}
+		return space.PathSum(1000000)
+	}))
+}
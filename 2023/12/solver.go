@@ -0,0 +1,20 @@
+package day12
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 12, 1, solver.Func(func(filename string) (any, error) {
+		rows, err := NewSpringRows(filename)
+		if err != nil {
+			return nil, err
+		}
+		return rows.SumArrangements(1), nil
+	}))
+	solver.Register(2023, 12, 2, solver.Func(func(filename string) (any, error) {
+		rows, err := NewSpringRows(filename)
+		if err != nil {
+			return nil, err
+		}
+		return rows.SumArrangements(5), nil
+	}))
+}
@@ -3,6 +3,7 @@ package day12
 import (
 	"bufio"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
@@ -57,246 +58,123 @@ func (sl *SpringList) NewWith(n int, state Spring) (rv SpringList) {
 	return
 }
 
-// Arrangements recursively computes the potential arrangements that can match spec by replacing unknown springs with either good or bad ones.
-func (sl *SpringList) Arrangements(spec Ints, unfoldFactor int) int {
-	return sl.findArrangements(0, 0, -1, spec, unfoldFactor)
-}
-
-// Returns true if pattern can match sl[from:from+len(pattern)] (e.g. each item in SL either directly matches pattern, or is unknown).
-func (sl *SpringList) canMatch(pattern SpringList, from int) bool {
-	if from+len(pattern) > len(*sl) {
-		glog.V(1).Infof("cannot match %s from %d in row of only %d", pattern, from, len(*sl))
-		return false
-	}
-	for n := 0; n < len(pattern); n++ {
-		if (*sl)[from+n] == S_UNKNOWN {
-			continue
-		}
-		if (*sl)[from+n] != pattern[n] {
-			glog.V(1).Infof("pattern mismatch at %d (pattern idx %d, expected %s got %s): %s vs %s", from+n, n, pattern[n], (*sl)[from+n], (*sl)[from:], pattern)
-			return false
+// Returns the lengths of each run of bad springs, ignoring unknowns.
+func (sl SpringList) Runs() (rv Ints) {
+	run := 0
+	for _, s := range sl {
+		if s == S_BAD {
+			run++
+		} else if run > 0 {
+			rv = append(rv, run)
+			run = 0
 		}
 	}
-	return true
+	if run > 0 {
+		rv = append(rv, run)
+	}
+	return
 }
 
-type Match struct {
-	Springs SpringList
-	Length  int
+// A point part way along the springs: the next spring to place, how many
+// runs of spec are already complete, and how long the current run is.
+type countKey struct {
+	pos, group, run int
 }
 
-// Matches returns the positions a "link" spring after a match of the pattern in spec could be, for all matches in sl.
-func (sl SpringList) potentialMatches(spec Ints, from int) (rv []Match) {
-	glog.V(1).Infof(" canMatchTil %s from %d (of %d)", sl, from, len(sl))
-	sI := 0
-	badTil := -1
-	for n := from; n < len(sl); n++ {
-		s := sl[n]
-		if s == S_UNKNOWN {
-			rv = append(rv, sl.NewWith(n, S_OK).potentialMatches(spec, from)...)
-			rv = append(rv, sl.NewWith(n, S_BAD).potentialMatches(spec, from)...)
-			return
-		}
-		if s == S_OK && badTil != -1 && n <= badTil {
-			glog.V(2).Infof("good spring at %d when expecting bad til %d in %s, %s", n, badTil, sl, spec)
-			return // found a good spring when expecting a run of bads
-		}
-		if s != S_BAD {
-			if n > badTil {
-				if sI >= len(spec) {
-					return []Match{{Springs: sl, Length: n}} // found end of sequence that matched spec.
-				}
-				badTil = -1 // reset expectation
-			}
-			continue
-		}
-
-		if badTil != -1 && n == badTil+1 {
-			// bad following expected end of sequence, when we need an OK!
-			glog.V(2).Infof("bad spring at %d needs to be OK in %s, %s", n, sl, spec)
-			return // too many bad springs
-		}
+// counter counts the ways the rest of the springs can be placed from each
+// point, remembering them so each point is only worked out once.
+type counter struct {
+	springs SpringList
+	spec    Ints
+	memo    map[countKey]*big.Int
+}
 
-		if badTil == -1 {
-			if sI >= len(spec) {
-				glog.Fatalf("bad spring at %d makes %d bad runs, when expecting %d in %s, %s", n, sI, len(spec), sl, spec)
-			}
-			// first bad spring in a run
-			badTil = n + spec[sI] - 1 // s counts as the first item in the run
-			sI++
-		} else {
-			if n > badTil {
-				glog.V(2).Infof("bad spring at %d exceeds expected run til %d, when expecting %d in %s, %s", n, badTil, sl, spec)
-				return // sequence too long
-			}
-		}
+// Whether the spring at k.pos can be good, and the point after it if so.
+func (c *counter) ok(k countKey) (countKey, bool) {
+	if c.springs[k.pos] == S_BAD {
+		return k, false
 	}
-	if badTil != -1 && badTil >= len(sl) {
-		// bad sequence ran off the end
-		glog.V(2).Infof("ran out of bad springs, when expecting til %d in %s of length %d with %s", badTil, sl, len(sl), spec)
-		return
+	if k.run == 0 {
+		return countKey{k.pos + 1, k.group, 0}, true
 	}
-	if sI != len(spec) {
-		glog.V(2).Infof("only matched %d/%d bad spring sequences in %s with %s", sI, len(spec), sl, spec)
-		return
+	if k.run == c.spec[k.group] {
+		return countKey{k.pos + 1, k.group + 1, 0}, true
 	}
-	return []Match{{Springs: sl, Length: len(sl)}}
+	return k, false // ends the run too soon
 }
 
-// Returns how many springs backwards the last bad spring was. Zero if no previous bad spring was found.
-func (sl *SpringList) lastBad(from int) int {
-	for n := from - 1; n >= 0; n-- {
-		if (*sl)[n] == S_BAD {
-			return from - n
-		}
+// Whether the spring at k.pos can be bad, and the point after it if so.
+func (c *counter) bad(k countKey) (countKey, bool) {
+	if c.springs[k.pos] == S_OK || k.group >= len(c.spec) || k.run >= c.spec[k.group] {
+		return k, false
 	}
-	return 0
+	return countKey{k.pos + 1, k.group, k.run + 1}, true
 }
 
-// findArrangements iteratively checks along the list of springs, recursing only when an unknown spring is found following an already valid
-// pattern.
-func (sl *SpringList) findArrangements(from, match, badTil int, spec Ints, matchLimit int) (rv int) {
-	glog.V(1).Infof(" %s%s from %d (of %d) at match %d", "", sl, from, len(*sl), match)
-
-	matches := sl.potentialMatches(spec, from)
-	match++
+// Whether the springs all placed at k make a complete arrangement.
+func (c *counter) done(k countKey) bool {
+	if k.run == 0 {
+		return k.group == len(c.spec)
+	}
+	return k.group == len(c.spec)-1 && k.run == c.spec[k.group]
+}
 
-MATCHES:
-	for _, m := range matches {
-		glog.V(1).Infof("Found %d match from %d to %d: %s", match, from, m.Length, m.Springs[from:m.Length])
-		for linkPos := m.Length; linkPos < len(*sl); linkPos++ {
-			spring := m.Springs[linkPos]
-			// don't need to check for BAD before unknown, OK here, because potentialMatches will only return options with a following OK/UNKNOWN or end.
-			if spring == S_OK {
-				continue
-			}
-			if spring == S_UNKNOWN {
-				if match != matchLimit {
-					// explore this path as a potential new start
-					glog.V(1).Infof("Exploring %d as BAD", linkPos)
-					next := m.Springs.NewWith(linkPos, S_BAD)
-					rv += next.findArrangements(linkPos, match, -1, spec, matchLimit)
-				}
-			} else if spring == S_BAD && match != matchLimit {
-				// must be the start of the next pattern
-				rv += m.Springs.findArrangements(linkPos, match, -1, spec, matchLimit)
-				break
-			} else {
-				glog.V(1).Infof("found cycle %d/%d prematurely ending at %d/%d", match, matchLimit, m.Length, len(*sl))
-				continue MATCHES
-			}
-		}
-		// hit the end without finding another BAD spring, so this is a good match.
-		if match == matchLimit {
-			rv++
+func (c *counter) count(k countKey) *big.Int {
+	if k.pos == len(c.springs) {
+		if c.done(k) {
+			return big.NewInt(1)
 		}
+		return big.NewInt(0)
+	}
+	if rv, found := c.memo[k]; found {
+		return rv
+	}
+	rv := new(big.Int)
+	if next, ok := c.ok(k); ok {
+		rv.Add(rv, c.count(next))
+	}
+	if next, ok := c.bad(k); ok {
+		rv.Add(rv, c.count(next))
 	}
+	c.memo[k] = rv
+	return rv
+}
 
-	return
+func newCounter(sl SpringList, spec Ints) *counter {
+	return &counter{springs: sl, spec: spec, memo: map[countKey]*big.Int{}}
 }
 
-// findArrangements iteratively checks along the list of springs, recursing only when an unknown spring is found following an already valid
-// pattern.
-/*
-func (sl *SpringList) oldfindArrangements(from, sI, badTil int, spec Ints, unfoldFactor int) int {
-	glog.Infof(" %s%s from %d (of %d)", "", sl, from, len(*sl))
-	for n := from; n < len(*sl); n++ {
-		s := (*sl)[n]
-		if s == S_UNKNOWN {
-			a := sl.NewWith(n, S_OK)
-			b := sl.NewWith(n, S_BAD)
-			return a.findArrangements(n, sI, badTil, spec, unfoldFactor) + b.findArrangements(n, sI, badTil, spec, unfoldFactor)
-		}
-		if s == S_OK {
-			if badTil != -1 && n <= badTil {
-				glog.V(2).Infof("good spring at %d when expecting bad til %d in %s, %s", n, badTil, sl, spec)
-				return 0 // found a good spring when expecting a run of bads
-			}
-			if n > badTil {
-				badTil = -1 // reset expectation
-				continue
+// Arrangements counts the ways the unknown springs can be replaced with good or bad ones so that the runs of bad springs match spec.
+func (sl SpringList) Arrangements(spec Ints) *big.Int {
+	return newCounter(sl, spec).count(countKey{})
+}
+
+// Enumerate returns every arrangement of the springs matching spec, or an error if there are more than limit of them.
+func (sl SpringList) Enumerate(spec Ints, limit int) ([]SpringList, error) {
+	c := newCounter(sl, spec)
+	if n := c.count(countKey{}); n.Cmp(big.NewInt(int64(limit))) > 0 {
+		return nil, fmt.Errorf("%s has %s arrangements for %s, more than %d", sl, n, spec, limit)
+	}
+	rv := []SpringList{}
+	var walk func(k countKey, placed SpringList)
+	walk = func(k countKey, placed SpringList) {
+		if k.pos == len(sl) {
+			if c.done(k) {
+				rv = append(rv, append(SpringList{}, placed...))
 			}
-			continue
+			return
 		}
-
-		if badTil != -1 && n == badTil+1 {
-			// bad following expected end of sequence, when we need an OK!
-			glog.V(2).Infof("bad spring at %d needs to be OK in %s, %s", n, sl, spec)
-			return 0 // too many bad springs
+		// Only follow choices that lead to some arrangement.
+		if next, ok := c.ok(k); ok && c.count(next).Sign() > 0 {
+			walk(next, append(placed, S_OK))
 		}
-
-		if badTil == -1 {
-			if sI >= (len(spec) * unfoldFactor) {
-				glog.V(2).Infof("bad spring at %d makes %d bad runs, when expecting %d in %s, %s", n, sI, len(spec), sl, spec)
-				return 0 // too many bad springs
-			}
-			eSi := sI
-			if unfoldFactor > 1 && sI >= len(spec) {
-				eSi = sI % len(spec)
-				if eSi == 0 {
-					linkSize := sl.lastBad(n) - 1 // 1 this spring, 1 the last bad spring
-					if linkSize == 0 {
-						glog.Fatalf("End of bad spring cycle in %s at %d with no previous bad spring!", sl, n)
-					}
-					cycLen := n - linkSize
-					cycN := (len(*sl) / cycLen) - 1 // -1 because first cycle is already up to n; only want remaining.
-					glog.Infof("Evaluating potential %d spring cycle (x%d) from %d-%d + %d links: %s", cycLen, cycN, 0, cycLen, linkSize, (*sl)[0:n])
-					cycI := 0
-					linkArrangements := 1
-					for {
-						cycS := n + (cycI * (cycLen + linkSize))
-						if cycS >= len(*sl) {
-							break
-						}
-						if !sl.canMatchTil(spec, cycS) {
-							return 0
-						}
-						for linkN := 0; linkN < linkSize; linkN++ {
-							linkPos := cycS + cycLen + linkN
-							if linkPos >= len(*sl) {
-								break
-							}
-							// non final cycles must be followed by an OK spring to "link" to the start of the next cycle of bad springs.
-							link := (*sl)[linkPos]
-							if link == S_BAD {
-								glog.Infof("Expected link at %d (%s) was BAD; from cycI=%d, cycS=%d", linkPos, link, cycI, cycS)
-								return 0
-							}
-							if link == S_UNKNOWN {
-								glog.Infof("Recursing in cycle at %d", linkPos)
-								next := sl.NewWith(linkPos, S_BAD)
-								linkArrangements += next.findArrangements(linkPos, 0, -1, spec, unfoldFactor-(sI/len(spec)))
-							}
-						}
-						cycI++
-					}
-					glog.Infof("Cycle is viable!")
-					return 1
-				}
-			}
-
-			// first bad spring in a run
-			badTil = n + spec[eSi] - 1 // s counts as the first item in the run
-			sI++
-		} else {
-			if n > badTil {
-				glog.V(2).Infof("bad spring at %d exceeds expected run til %d, when expecting %d in %s, %s", n, badTil, sl, spec)
-				return 0 // sequence too long
-			}
+		if next, ok := c.bad(k); ok && c.count(next).Sign() > 0 {
+			walk(next, append(placed, S_BAD))
 		}
 	}
-	if badTil != -1 && badTil >= len(*sl) {
-		// bad sequence ran off the end
-		glog.V(2).Infof("ran out of bad springs, when expecting til %d in %s of length %d with %s", badTil, sl, len(*sl), spec)
-		return 0
-	}
-	if sI != len(spec) {
-		glog.V(2).Infof("only matched %d/%d bad spring sequences in %s with %s", sI, len(spec), sl, spec)
-		return 0
-	}
-	return 1
+	walk(countKey{}, SpringList{})
+	return rv, nil
 }
-*/
 
 type SpringRow struct {
 	Springs SpringList
@@ -304,18 +182,28 @@ type SpringRow struct {
 }
 
 // Returns a new SpringRow based on this row, but unfolded!
-func (sr SpringRow) Unfold() (nr SpringRow) {
-	nr.BadRuns = append(nr.BadRuns, sr.BadRuns...)
-	for n := 0; n < 5; n++ {
-		nr.Springs = append(nr.Springs, sr.Springs...)
-		//nr.BadRuns = append(nr.BadRuns, sr.BadRuns...)
-		if n != 4 {
+func (sr SpringRow) Unfold() SpringRow {
+	return sr.UnfoldBy(5)
+}
+
+// Returns a new SpringRow with factor copies of this row's springs, joined by unknown springs, and of its runs.
+func (sr SpringRow) UnfoldBy(factor int) (nr SpringRow) {
+	for n := 0; n < factor; n++ {
+		if n > 0 {
 			nr.Springs = append(nr.Springs, S_UNKNOWN)
 		}
+		nr.Springs = append(nr.Springs, sr.Springs...)
+		nr.BadRuns = append(nr.BadRuns, sr.BadRuns...)
 	}
 	return
 }
 
+// Counts the arrangements of this row once unfolded by factor.
+func (sr SpringRow) Arrangements(factor int) *big.Int {
+	nr := sr.UnfoldBy(factor)
+	return nr.Springs.Arrangements(nr.BadRuns)
+}
+
 type SpringRows []SpringRow
 
 func NewSpringRows(filename string) (rows SpringRows, err error) {
@@ -352,18 +240,19 @@ func (r *SpringRows) Unfold() {
 	}
 }
 
-func (r *SpringRows) Arrangements(row int, unfoldFactor int) int {
+func (r *SpringRows) Arrangements(row int, unfoldFactor int) (*big.Int, error) {
 	if row < 0 || row >= len(*r) {
-		glog.Fatalf("invalid row index: %d", row)
+		return nil, fmt.Errorf("invalid row index: %d", row)
 	}
-	sr := (*r)[row]
-	return sr.Springs.Arrangements(sr.BadRuns, unfoldFactor)
+	return (*r)[row].Arrangements(unfoldFactor), nil
 }
 
-func (r *SpringRows) SumArrangements(unfoldFactor int) (rv int) {
+func (r *SpringRows) SumArrangements(unfoldFactor int) *big.Int {
+	rv := new(big.Int)
 	for n, sr := range *r {
-		glog.V(1).Infof("Starting row %d: %s (len=%d), %s", n, sr, len(sr.Springs), sr.BadRuns)
-		rv += r.Arrangements(n, unfoldFactor)
+		a := sr.Arrangements(unfoldFactor)
+		glog.V(1).Infof("Row %d: %s (len=%d), %s has %s arrangements", n, sr.Springs, len(sr.Springs), sr.BadRuns, a)
+		rv.Add(rv, a)
 	}
-	return
+	return rv
 }
@@ -1,6 +1,9 @@
 package day12
 
 import (
+	"math/big"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/golang/glog"
@@ -23,53 +26,32 @@ func Test_Sample(t *testing.T) {
 	rows, err := NewSpringRows("sample")
 	require.NoError(t, err)
 	assert.Len(t, rows, 6)
-	assert.Equal(t, 21, rows.SumArrangements(1))
+	assert.Equal(t, "21", rows.SumArrangements(1).String())
 
-	expect := []int{1, 4, 1, 1, 4, 10}
+	expect := []int64{1, 4, 1, 1, 4, 10}
 	for n, e := range expect {
 		glog.V(1).Infof("Test Case %d", n)
-		assert.Equal(t, e, rows.Arrangements(n, 1), "sample %d", n)
+		a, err := rows.Arrangements(n, 1)
+		require.NoError(t, err)
+		assert.Equal(t, e, a.Int64(), "sample %d", n)
 	}
+	_, err = rows.Arrangements(6, 1)
+	assert.Error(t, err)
 }
 
 func Test_Part1(t *testing.T) {
 	rows, err := NewSpringRows("input")
 	require.NoError(t, err)
 	a := rows.SumArrangements(1)
-	assert.Equal(t, 7407, a)
+	assert.Equal(t, "7407", a.String())
 
-	t.Logf("Arrangement sum is %d", a)
+	t.Logf("Arrangement sum is %s", a)
 }
 
-func Test_CanMatch(t *testing.T) {
-	sl := SpringList{S_OK, S_BAD}
-	assert.True(t, sl.canMatch(SpringList{S_OK, S_BAD}, 0))
-	assert.True(t, sl.canMatch(SpringList{S_OK}, 0))
-	assert.True(t, sl.canMatch(SpringList{S_BAD}, 1))
-
-	assert.False(t, sl.canMatch(SpringList{S_OK, S_BAD}, 1))
-	assert.False(t, sl.canMatch(SpringList{S_OK}, 1))
-	assert.False(t, sl.canMatch(SpringList{S_BAD}, 0))
-
-	sl = SpringList{S_OK, S_UNKNOWN, S_BAD}
-	assert.True(t, sl.canMatch(SpringList{S_OK, S_OK}, 0))
-	assert.True(t, sl.canMatch(SpringList{S_BAD, S_BAD}, 1))
-	assert.True(t, sl.canMatch(SpringList{S_OK, S_BAD}, 1))
-	assert.True(t, sl.canMatch(SpringList{S_OK, S_BAD}, 0))
-
-	assert.False(t, sl.canMatch(SpringList{S_BAD, S_OK}, 0))
-}
-
-func Test_LastBad(t *testing.T) {
-	sl := SpringList{S_OK, S_BAD, S_BAD, S_OK, S_BAD, S_OK, S_OK, S_BAD}
-	assert.Equal(t, 0, sl.lastBad(0))
-	assert.Equal(t, 0, sl.lastBad(1))
-	assert.Equal(t, 1, sl.lastBad(2))
-	assert.Equal(t, 1, sl.lastBad(3))
-	assert.Equal(t, 2, sl.lastBad(4))
-	assert.Equal(t, 1, sl.lastBad(5))
-	assert.Equal(t, 2, sl.lastBad(6))
-	assert.Equal(t, 3, sl.lastBad(7))
+func Test_Runs(t *testing.T) {
+	assert.Equal(t, Ints(nil), SpringList{S_OK, S_UNKNOWN}.Runs())
+	assert.Equal(t, Ints{1, 2}, SpringList{S_BAD, S_OK, S_BAD, S_BAD}.Runs())
+	assert.Equal(t, Ints{3}, SpringList{S_OK, S_BAD, S_BAD, S_BAD, S_OK}.Runs())
 }
 
 func Test_Unfold(t *testing.T) {
@@ -80,42 +62,117 @@ func Test_Unfold(t *testing.T) {
 	nr := sr.Unfold()
 	assert.Equal(t, SpringList{S_OK, S_BAD, S_UNKNOWN, S_OK, S_BAD, S_UNKNOWN, S_OK, S_BAD, S_UNKNOWN, S_OK, S_BAD, S_UNKNOWN, S_OK, S_BAD}, nr.Springs)
 	assert.Equal(t, Ints{1, 1, 1, 1, 1}, nr.BadRuns)
+	assert.Equal(t, sr, sr.UnfoldBy(1))
 }
 
 func Test_Sample_Part2(t *testing.T) {
 	rows, err := NewSpringRows("sample")
 	require.NoError(t, err)
 	assert.Len(t, rows, 6)
-	rows.Unfold()
 
-	assert.Equal(t, 525152, rows.SumArrangements(5))
+	assert.Equal(t, "525152", rows.SumArrangements(5).String())
 
-	expect := []int{1, 16384, 1, 16, 2500, 506250}
+	expect := []int64{1, 16384, 1, 16, 2500, 506250}
 	for n, e := range expect {
-		glog.Infof("Test Case %d", n)
-		assert.Equal(t, e, rows.Arrangements(n, 5), "sample %d", n)
+		a, err := rows.Arrangements(n, 5)
+		require.NoError(t, err)
+		assert.Equal(t, e, a.Int64(), "sample %d", n)
 	}
-}
 
-func Test_Part2(t *testing.T) {
-	rows, err := NewSpringRows("input")
-	require.NoError(t, err)
+	// Unfolding first is the same as unfolding while counting.
 	rows.Unfold()
-
-	t.Logf("Arrangement sum is %d", rows.SumArrangements(5))
+	assert.Equal(t, "525152", rows.SumArrangements(1).String())
 }
 
-func Test_Debug1(t *testing.T) {
+// Unfolding far enough needs more than an int.
+func Test_BigUnfold(t *testing.T) {
 	rows, err := NewSpringRows("sample")
 	require.NoError(t, err)
-	glog.Infof("%d Springs", len(rows[5].Springs))
-	assert.Equal(t, 10, rows.Arrangements(5, 1))
+	a := rows[5].Arrangements(20)
+	assert.False(t, a.IsInt64())
+	// Each extra fold of ?###???????? multiplies the count by 15 (see the sample's 10, 150, 2250, ...).
+	want := big.NewInt(10)
+	for n := 1; n < 20; n++ {
+		want.Mul(want, big.NewInt(15))
+	}
+	assert.Equal(t, want, a)
 }
 
-func Test_Debug(t *testing.T) {
+// Every enumerated arrangement fits the springs and the runs, and there
+// are as many as were counted.
+func Test_Enumerate(t *testing.T) {
 	rows, err := NewSpringRows("sample")
 	require.NoError(t, err)
-	rows.Unfold()
-	glog.Infof("%d Springs", len(rows[2].Springs))
-	assert.Equal(t, 1, rows.Arrangements(2, 5))
+	for factor := 1; factor <= 3; factor++ {
+		for n, sr := range rows {
+			nr := sr.UnfoldBy(factor)
+			all, err := nr.Springs.Enumerate(nr.BadRuns, 100000)
+			if err != nil {
+				assert.Equal(t, 1, nr.Springs.Arrangements(nr.BadRuns).Cmp(big.NewInt(100000)), "row %d x%d", n, factor)
+				continue
+			}
+			assert.Equal(t, nr.Springs.Arrangements(nr.BadRuns).Int64(), int64(len(all)), "row %d x%d", n, factor)
+			seen := map[string]bool{}
+			for _, a := range all {
+				require.Len(t, a, len(nr.Springs))
+				for i, s := range nr.Springs {
+					if s != S_UNKNOWN {
+						require.Equal(t, s, a[i], "%s for %s", a, nr.Springs)
+					}
+					require.NotEqual(t, S_UNKNOWN, a[i])
+				}
+				require.Equal(t, nr.BadRuns, a.Runs(), "%s", a)
+				seen[a.String()] = true
+			}
+			assert.Len(t, seen, len(all))
+		}
+	}
+
+	all, err := rows[1].Springs.Enumerate(rows[1].BadRuns, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"..#...#...###.", "..#..#....###.", ".#....#...###.", ".#...#....###."}, strs(all))
+	_, err = rows[1].Springs.Enumerate(rows[1].BadRuns, 3)
+	assert.Error(t, err)
+}
+
+func strs(l []SpringList) (rv []string) {
+	for _, sl := range l {
+		rv = append(rv, sl.String())
+	}
+	return
+}
+
+// Counting agrees with trying every way to fill in the unknowns.
+func Test_ArrangementsBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 300; n++ {
+		sl := SpringList{}
+		for i := r.Intn(12); i > 0; i-- {
+			sl = append(sl, Spring(r.Intn(3)))
+		}
+		spec := Ints{}
+		for i := r.Intn(4); i > 0; i-- {
+			spec = append(spec, r.Intn(3)+1)
+		}
+		assert.Equal(t, int64(brute(sl, spec)), sl.Arrangements(spec).Int64(), "%s %s", sl, spec)
+	}
+}
+
+func brute(sl SpringList, spec Ints) int {
+	for i, s := range sl {
+		if s == S_UNKNOWN {
+			return brute(sl.NewWith(i, S_OK), spec) + brute(sl.NewWith(i, S_BAD), spec)
+		}
+	}
+	if slices.Equal(sl.Runs(), spec) {
+		return 1
+	}
+	return 0
+}
+
+func Test_Part2(t *testing.T) {
+	rows, err := NewSpringRows("input")
+	require.NoError(t, err)
+
+	t.Logf("Arrangement sum is %s", rows.SumArrangements(5))
 }
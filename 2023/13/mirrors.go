@@ -3,260 +3,172 @@ package day13
 import (
 	"bufio"
 	"fmt"
+	"math/bits"
 	"os"
 
 	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 type MirrorValley struct {
-	Mirrors []Grid
-	Smudged bool
-}
-
-func (m MirrorValley) ReflectsVertical(g Grid, ignoreCol int) (reflectsOn int, nearMatches []Mismatch, otherMismatches []Mismatch, ok bool) {
-	reflectsOn = -1
-	for col := 2; col <= g.maxcol; col++ {
-		if col == ignoreCol {
-			continue
-		}
-		mismatched := m.colMismatch(g, col, col-1)
-		if len(mismatched) != 0 {
-			for _, mm := range mismatched {
-				mm.col = col
-				otherMismatches = append(otherMismatches, mm)
-			}
-			continue
-		}
-		nm, colOk := m.checkColReflects(g, col)
-		if colOk {
-			if reflectsOn == -1 {
-				glog.Infof("Found good reflection around col %d", col)
-				reflectsOn = col
-			} else {
-				glog.Infof("Ignoring additional reflection around col %d", col)
+	Mirrors  []Grid[BaseCell]
+	Patterns []Pattern
+	Smudged  bool
+}
+
+// Pattern is a mirror's rows and columns as bit masks of where its rocks
+// are, so comparing two rows or columns is an xor and a popcount.
+type Pattern struct {
+	// Bit c-1 of Rows[r-1] (and bit r-1 of Cols[c-1]) is set if there is a
+	// rock at r,c.
+	Rows, Cols []uint64
+}
+
+func NewPattern(g Grid[BaseCell]) (p Pattern, err error) {
+	if g.MaxRow() > 64 || g.MaxCol() > 64 {
+		return p, fmt.Errorf("%dx%d mirror is too big, at most 64x64 fits", g.MaxRow(), g.MaxCol())
+	}
+	p.Rows = make([]uint64, g.MaxRow())
+	p.Cols = make([]uint64, g.MaxCol())
+	for r := 1; r <= g.MaxRow(); r++ {
+		for c := 1; c <= g.MaxCol(); c++ {
+			if g.C(Pos{Row: r, Col: c}).Symbol == "#" {
+				p.Rows[r-1] |= 1 << (c - 1)
+				p.Cols[c-1] |= 1 << (r - 1)
 			}
-			ok = true
-		} else {
-			nearMatches = append(nearMatches, nm...)
 		}
 	}
 	return
 }
 
-func (m MirrorValley) ReflectsHorizontal(g Grid, ignoreRow int) (reflectsOn int, nearMatches []Mismatch, otherMismatches []Mismatch, ok bool) {
-	reflectsOn = -1
-	for row := 2; row <= g.maxrow; row++ {
-		if row == ignoreRow {
-			continue
-		}
-		mismatched := m.rowMismatch(g, row, row-1)
-		if len(mismatched) != 0 {
-			for _, mm := range mismatched {
-				mm.row = row
-				otherMismatches = append(otherMismatches, mm)
-			}
-			continue
-		}
-		nm, rowOk := m.checkRowReflects(g, row)
-		if rowOk {
-			if reflectsOn == -1 {
-				glog.Infof("Found good reflection around row %d", row)
-				reflectsOn = row
-			} else {
-				glog.Infof("Ignoring additional reflection around row %d", row)
-			}
-			ok = true
-		} else {
-			nearMatches = append(nearMatches, nm...)
-		}
-	}
-	return
+// Smudge is a cell that differs from its reflection; cleaning either of
+// the two would make them match.
+type Smudge struct {
+	Pos, Reflection Pos
 }
 
-func (m MirrorValley) colMismatch(g Grid, c1, c2 int) (rv []Mismatch) {
-	for r := 1; r <= g.maxrow; r++ {
-		p1, p2 := Pos{r, c1}, Pos{r, c2}
-		v1, v2 := g.C(p1), g.C(p2)
-		if v1.(BaseCell).Symbol != v2.(BaseCell).Symbol {
-			glog.V(1).Infof("mismatch between %s (%s) and %s (%s)", p1, v1, p2, v2)
-			rv = append(rv, Mismatch{a: c1, b: c2, idx: r})
-		}
-	}
-	if len(rv) == 0 {
-		glog.V(1).Infof("found matching cols %d and %d", c1, c2)
-	}
-	return
+// Reflection is a line a pattern (almost) reflects across.
+type Reflection struct {
+	// Whether the line runs between columns, rather than between rows.
+	Vertical bool
+	// How many columns (or rows) are before the line.
+	Before int
+	// The cells that don't match their reflection.
+	Smudges []Smudge
 }
 
-func (m MirrorValley) rowMismatch(g Grid, r1, r2 int) (rv []Mismatch) {
-	for c := 1; c <= g.maxcol; c++ {
-		p1, p2 := Pos{r1, c}, Pos{r2, c}
-		v1, v2 := g.C(p1), g.C(p2)
-		if v1.(BaseCell).Symbol != v2.(BaseCell).Symbol {
-			glog.V(1).Infof("mismatch between %s (%s) and %s (%s)", p1, v1, p2, v2)
-			rv = append(rv, Mismatch{a: r1, b: r2, idx: c})
-		}
+func (r Reflection) String() string {
+	if r.Vertical {
+		return fmt.Sprintf("between cols %d,%d with %d smudges", r.Before, r.Before+1, len(r.Smudges))
 	}
-	if len(rv) == 0 {
-		glog.V(1).Infof("found matching rows %d and %d", r1, r2)
-	}
-	return
-}
-
-type Mismatch struct {
-	row int
-	col int
-
-	a   int
-	b   int
-	idx int
+	return fmt.Sprintf("between rows %d,%d with %d smudges", r.Before, r.Before+1, len(r.Smudges))
 }
 
-func (nm Mismatch) String() string {
-	var desc string
-	var id int
-	if nm.row != 0 {
-		desc = "row"
-		id = nm.row
-	} else if nm.col != 0 {
-		desc = "col"
-		id = nm.col
-	} else {
-		glog.Fatalf("invalid Mismatch, neither row nor col set: %v", nm)
+func (r Reflection) Score() int {
+	if r.Vertical {
+		return r.Before
 	}
-	return fmt.Sprintf("Mismatch between %ss %d,%d idx %d (mirroring around %s %d)", desc, nm.a, nm.b, nm.idx, desc, id)
+	return r.Before * 100
 }
 
-// checks the reflection point before row matches the rest of the field.
-func (m MirrorValley) checkRowReflects(g Grid, row int) (rv []Mismatch, ok bool) {
-	ok = true
-	step := 1
-	for {
-		l := row - 1 - step
-		r := row + step
-		if l < 1 || r > g.maxrow {
-			break
+// Returns where the masks reflect with exactly k bits different, as how
+// many masks are before each line.
+func reflects(lines []uint64, k int) (rv []int) {
+	for before := 1; before < len(lines); before++ {
+		diff := 0
+		for a, b := before-1, before; a >= 0 && b < len(lines) && diff <= k; a, b = a-1, b+1 {
+			diff += bits.OnesCount64(lines[a] ^ lines[b])
 		}
-		for _, mm := range m.rowMismatch(g, l, r) {
-			mm.row = row
-			rv = append(rv, mm)
-			ok = false
+		if diff == k {
+			rv = append(rv, before)
 		}
-		step++
 	}
 	return
 }
 
-// checks the reflection point before col matches the rest of the field.
-func (m MirrorValley) checkColReflects(g Grid, col int) (rv []Mismatch, ok bool) {
-	ok = true
-	step := 1
-	for {
-		t := col - 1 - step
-		b := col + step
-		if t < 1 || b > g.maxcol {
-			break
-		}
-		for _, mm := range m.colMismatch(g, t, b) {
-			mm.col = col
-			rv = append(rv, mm)
-			ok = false
+// Returns the cells of lines that differ across the line after before,
+// as pos(line, bit) gives them.
+func smudges(lines []uint64, before int, pos func(line, bit int) Pos) (rv []Smudge) {
+	for a, b := before-1, before; a >= 0 && b < len(lines); a, b = a-1, b+1 {
+		for x := lines[a] ^ lines[b]; x != 0; x &= x - 1 {
+			bit := bits.TrailingZeros64(x)
+			rv = append(rv, Smudge{pos(a, bit), pos(b, bit)})
 		}
-		step++
 	}
 	return
 }
 
-func (m MirrorValley) Score(g Grid) int {
-	return m.ScoreIgnoring(g, -1, -1)
+// Returns every line the pattern reflects across with exactly k smudges,
+// horizontal lines first.
+func (p Pattern) Reflections(k int) (rv []Reflection) {
+	for _, before := range reflects(p.Rows, k) {
+		rv = append(rv, Reflection{Before: before, Smudges: smudges(p.Rows, before, func(line, bit int) Pos {
+			return Pos{Row: line + 1, Col: bit + 1}
+		})})
+	}
+	for _, before := range reflects(p.Cols, k) {
+		rv = append(rv, Reflection{Vertical: true, Before: before, Smudges: smudges(p.Cols, before, func(line, bit int) Pos {
+			return Pos{Row: bit + 1, Col: line + 1}
+		})})
+	}
+	return
 }
 
-func (m MirrorValley) ScoreIgnoring(g Grid, iR, iC int) int {
-	r, _, _, ok := m.ReflectsHorizontal(g, iR)
-	if ok {
-		return (r - 1) * 100
+// Returns the first line the pattern reflects across with exactly k
+// smudges.
+func (p Pattern) Reflection(k int) (Reflection, error) {
+	rs := p.Reflections(k)
+	if len(rs) == 0 {
+		return Reflection{}, fmt.Errorf("no reflection with %d smudges", k)
 	}
-	c, _, _, ok := m.ReflectsVertical(g, iC)
-	if ok {
-		return c - 1
+	for _, r := range rs[1:] {
+		glog.Infof("Ignoring additional reflection %s", r)
 	}
-	return 0
+	return rs[0], nil
 }
 
-var flip = map[string]string{
-	"#": ".",
-	".": "#",
+func (m MirrorValley) Score(g Grid[BaseCell]) int {
+	return m.scoreWith(g, 0)
 }
 
-func (m MirrorValley) FlipCell(c Cell) BaseCell {
-	return BaseCell{
-		id:     c.(BaseCell).id,
-		Symbol: flip[c.(BaseCell).Symbol],
-	}
+func (m MirrorValley) ScoreSmudged(g Grid[BaseCell]) int {
+	return m.scoreWith(g, 1)
 }
 
-func (m MirrorValley) ScoreSmudged(g Grid) int {
-	var mmReflected, mmOther []Mismatch
-	r, mmH, aMMh, _ := m.ReflectsHorizontal(g, -1)
-	mmReflected = append(mmReflected, mmH...)
-	mmOther = append(mmOther, aMMh...)
-	c, mmV, aMMv, _ := m.ReflectsVertical(g, -1)
-	mmReflected = append(mmReflected, mmV...)
-	mmOther = append(mmOther, aMMv...)
-	glog.Infof("Found %d reflected, and %d other mismatches to try", len(mmReflected), len(mmOther))
-	for _, mm := range [][]Mismatch{mmReflected, mmOther} {
-		scores := map[int]int{}
-		for _, t := range mm {
-			var aPos, bPos Pos
-			if t.col > 0 {
-				aPos = Pos{t.idx, t.a}
-				bPos = Pos{t.idx, t.b}
-			} else if t.row > 0 {
-				aPos = Pos{t.a, t.idx}
-				bPos = Pos{t.b, t.idx}
-			} else {
-				glog.Fatalf("invalid mismatch: %#v", t)
-			}
-			for _, p := range []Pos{aPos, bPos} {
-				g2 := g.Copy()
-				g2.SetC(p, m.FlipCell(g.C(p)))
-				glog.V(1).Infof("Flipping %s", p)
-				score := m.ScoreIgnoring(g2, r, c)
-				if score != 0 {
-					glog.Infof("Flipping %s - Got score of %d (from %s)", p, score, t)
-					scores[score]++
-				} else {
-					glog.V(1).Infof(" -  doesn't give a reflection")
-				}
-			}
-		}
-		if len(scores) == 0 {
-			continue
-		} else if len(scores) > 1 {
-			glog.Fatalf("Got %d possible scores!", len(scores))
-		} else {
-			for score := range scores {
-				return score
-			}
-		}
+func (m MirrorValley) scoreWith(g Grid[BaseCell], k int) int {
+	p, err := NewPattern(g)
+	if err != nil {
+		glog.Errorf("%v", err)
+		return 0
 	}
-	glog.Errorf("Found no possible reflections!")
-	g.Print()
-	return 0
+	r, err := p.Reflection(k)
+	if err != nil {
+		glog.Errorf("%v", err)
+		g.Print()
+		return 0
+	}
+	return r.Score()
 }
 
-func (m MirrorValley) ScoreSum() (rv int) {
-	for _, g := range m.Mirrors {
-		rv += m.Score(g)
+// Sums the scores of every mirror's reflection with exactly k smudges.
+func (m MirrorValley) ScoreSumWith(k int) (rv int, err error) {
+	for n, p := range m.Patterns {
+		r, err := p.Reflection(k)
+		if err != nil {
+			return 0, fmt.Errorf("mirror %d: %w", n+1, err)
+		}
+		rv += r.Score()
 	}
 	return
 }
 
-func (m MirrorValley) ScoreSmudgedSum() (rv int) {
-	for _, g := range m.Mirrors {
-		rv += m.ScoreSmudged(g)
-	}
-	return
+func (m MirrorValley) ScoreSum() (int, error) {
+	return m.ScoreSumWith(0)
+}
+
+func (m MirrorValley) ScoreSmudgedSum() (int, error) {
+	return m.ScoreSumWith(1)
 }
 
 func NewMirrorValley(filename string) (valley MirrorValley, err error) {
@@ -270,10 +182,15 @@ func NewMirrorValley(filename string) (valley MirrorValley, err error) {
 
 	for {
 		g := NewGridFromScanner[BaseCell](s)
-		if g.maxrow == -1 {
+		if g.MaxRow() == -1 {
 			break
 		}
+		p, err := NewPattern(g)
+		if err != nil {
+			return valley, err
+		}
 		valley.Mirrors = append(valley.Mirrors, g)
+		valley.Patterns = append(valley.Patterns, p)
 	}
 	return
 }
@@ -1,10 +1,14 @@
 package day13
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/mattbnz/aoc/lib/answers/answerstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 func Test_Sample(t *testing.T) {
@@ -12,23 +16,33 @@ func Test_Sample(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, valley.Mirrors, 2)
 
-	p, _, _, ok := valley.ReflectsVertical(valley.Mirrors[0], -1)
-	assert.True(t, ok)
-	assert.Equal(t, 6, p)
+	assert.Equal(t, []Reflection{{Vertical: true, Before: 5}}, valley.Patterns[0].Reflections(0))
 	assert.Equal(t, 5, valley.Score(valley.Mirrors[0]))
 
-	p, _, _, ok = valley.ReflectsHorizontal(valley.Mirrors[1], -1)
-	assert.True(t, ok)
-	assert.Equal(t, 5, p)
+	assert.Equal(t, []Reflection{{Before: 4}}, valley.Patterns[1].Reflections(0))
 	assert.Equal(t, 400, valley.Score(valley.Mirrors[1]))
 
-	assert.Equal(t, 405, valley.ScoreSum())
+	sum, err := valley.ScoreSum()
+	require.NoError(t, err)
+	assert.Equal(t, 405, sum)
+}
+
+func Test_Pattern(t *testing.T) {
+	valley, err := NewMirrorValley("sample")
+	require.NoError(t, err)
+	p := valley.Patterns[1]
+	require.Len(t, p.Rows, 7)
+	require.Len(t, p.Cols, 9)
+	assert.Equal(t, uint64(0b100110001), p.Rows[0]) // #...##..#
+	assert.Equal(t, uint64(0b1011011), p.Cols[0])   // the first column, top down
 }
 
 func Test_Part1(t *testing.T) {
 	valley, err := NewMirrorValley("input")
 	require.NoError(t, err)
-	t.Logf("Sum is %d", valley.ScoreSum())
+	sum, err := valley.ScoreSum()
+	require.NoError(t, err)
+	t.Logf("Sum is %d", sum)
 }
 
 func Test_Sample_Part2(t *testing.T) {
@@ -38,23 +52,63 @@ func Test_Sample_Part2(t *testing.T) {
 
 	assert.Equal(t, 300, valley.ScoreSmudged(valley.Mirrors[0]))
 	assert.Equal(t, 100, valley.ScoreSmudged(valley.Mirrors[1]))
-	assert.Equal(t, 400, valley.ScoreSmudgedSum())
+	sum, err := valley.ScoreSmudgedSum()
+	require.NoError(t, err)
+	assert.Equal(t, 400, sum)
+
+	// The smudges the puzzle points out.
+	r, err := valley.Patterns[0].Reflection(1)
+	require.NoError(t, err)
+	assert.Equal(t, []Smudge{{Pos{Row: 1, Col: 1}, Pos{Row: 6, Col: 1}}}, r.Smudges)
+	r, err = valley.Patterns[1].Reflection(1)
+	require.NoError(t, err)
+	assert.Equal(t, []Smudge{{Pos{Row: 1, Col: 5}, Pos{Row: 2, Col: 5}}}, r.Smudges)
 }
 
-func Test_Part2(t *testing.T) {
-	valley, err := NewMirrorValley("input")
+func pattern(t *testing.T, rows ...string) Pattern {
+	g := NewGrid[BaseCell](strings.NewReader(strings.Join(rows, "\n")))
+	p, err := NewPattern(g)
 	require.NoError(t, err)
-	score := valley.ScoreSmudgedSum()
-	assert.Greater(t, score, 21356) // first guess.
-	t.Logf("Sum is %d", score)
+	return p
 }
 
-func Test_Debug(t *testing.T) {
+// Cleaning the reported smudges leaves a perfect reflection, for any
+// number of smudges.
+func Test_Smudges(t *testing.T) {
+	p := pattern(t,
+		"#.##..",
+		"..#.#.",
+		"##...#",
+		"##...#",
+		"..#.#.",
+		"#.##..",
+	)
+	for k := 0; k <= 4; k++ {
+		for _, r := range p.Reflections(k) {
+			require.Len(t, r.Smudges, k, r)
+			cleaned := Pattern{Rows: append([]uint64{}, p.Rows...), Cols: append([]uint64{}, p.Cols...)}
+			for _, s := range r.Smudges {
+				cleaned.Rows[s.Pos.Row-1] ^= 1 << (s.Pos.Col - 1)
+				cleaned.Cols[s.Pos.Col-1] ^= 1 << (s.Pos.Row - 1)
+			}
+			assert.Contains(t, cleaned.Reflections(0), Reflection{Vertical: r.Vertical, Before: r.Before}, "%d: %s", k, r)
+		}
+	}
+	assert.Equal(t, []Reflection{{Before: 3}}, p.Reflections(0))
+
+	_, err := p.Reflection(40)
+	assert.Error(t, err)
+
+	wide := NewGrid[BaseCell](strings.NewReader(strings.Repeat(".", 65)))
+	_, err = NewPattern(wide)
+	assert.Error(t, err)
+}
+
+func Test_Part2(t *testing.T) {
 	valley, err := NewMirrorValley("input")
 	require.NoError(t, err)
-	mirror := valley.Mirrors[len(valley.Mirrors)-3]
-
-	mirror.PrintNumbered()
-	//t.Logf("Original Score: %d", valley.Score(mirror))
-	t.Logf("Smudged Score: %d", valley.ScoreSmudged(mirror))
+	score, err := valley.ScoreSmudgedSum()
+	require.NoError(t, err)
+	answerstest.Assert(t, 2, score)
+	t.Logf("Sum is %d", score)
 }
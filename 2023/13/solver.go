@@ -0,0 +1,20 @@
+package day13
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 13, 1, solver.Func(func(filename string) (any, error) {
+		valley, err := NewMirrorValley(filename)
+		if err != nil {
+			return nil, err
+		}
+		return valley.ScoreSum()
+	}))
+	solver.Register(2023, 13, 2, solver.Func(func(filename string) (any, error) {
+		valley, err := NewMirrorValley(filename)
+		if err != nil {
+			return nil, err
+		}
+		return valley.ScoreSmudgedSum()
+	}))
+}
@@ -5,13 +5,10 @@ import (
 	"os"
 
 	"github.com/golang/glog"
-)
-
-type DishCell struct {
-	BaseCell
-}
 
-var _ Cell = &DishCell{}
+	"github.com/mattbnz/aoc/lib/cycle"
+	. "github.com/mattbnz/aoc/lib/grid"
+)
 
 type Key struct {
 	GridKey string
@@ -19,9 +16,9 @@ type Key struct {
 }
 
 type Dish struct {
-	Grid
+	Grid[BaseCell]
 
-	Cache     map[Key]Grid
+	Cache     map[Key]Grid[BaseCell]
 	hit, miss int
 	checks    int
 }
@@ -33,35 +30,35 @@ func NewDish(filename string) (dish Dish, err error) {
 	}
 	defer f.Close()
 
-	dish.Grid = NewGrid[DishCell](f)
+	dish.Grid = NewGrid[BaseCell](f)
 	return
 }
 
 // TODO - move this to Grid?
 func (d *Dish) Key() (key string) {
-	d.Each(func(_ Pos, c Cell) bool {
-		key += c.(BaseCell).Symbol
+	d.Each(func(_ Pos, c BaseCell) bool {
+		key += c.Symbol
 		return true
 	})
 	return
 }
 
 func (d *Dish) RowKey(row int) (key string) {
-	for c := 0; c <= d.maxcol; c++ {
-		key += d.C(Pos{row, c}).(BaseCell).Symbol
+	for c := 0; c <= d.MaxCol(); c++ {
+		key += d.C(Pos{Row: row, Col: c}).Symbol
 	}
 	return
 }
 func (d *Dish) ColKey(col int) (key string) {
-	for r := 0; r <= d.maxrow; r++ {
-		key += d.C(Pos{r, col}).(BaseCell).Symbol
+	for r := 0; r <= d.MaxRow(); r++ {
+		key += d.C(Pos{Row: r, Col: col}).Symbol
 	}
 	return
 }
 
 func (d *Dish) SliceKey(a, b, bInc int, bEnd func(int) bool, makePos func(int, int) Pos) (key string) {
 	for i := b; bEnd(i); i += bInc {
-		key += d.C(makePos(i, a)).(BaseCell).Symbol
+		key += d.C(makePos(i, a)).Symbol
 	}
 	return
 }
@@ -83,28 +80,28 @@ func (d *Dish) Tilt(dir CardinalDirection) {
 	switch dir {
 	case NORTH:
 		a, aInc = 1, 1
-		aEnd = func(i int) bool { return i <= d.maxcol }
+		aEnd = func(i int) bool { return i <= d.MaxCol() }
 		b, bInc = 1, 1
-		bEnd = func(i int) bool { return i <= d.maxrow }
-		makePos = func(row, col int) Pos { return Pos{row, col} }
+		bEnd = func(i int) bool { return i <= d.MaxRow() }
+		makePos = func(row, col int) Pos { return Pos{Row: row, Col: col} }
 	case WEST:
 		a, aInc = 1, 1
-		aEnd = func(i int) bool { return i <= d.maxrow }
+		aEnd = func(i int) bool { return i <= d.MaxRow() }
 		b, bInc = 1, 1
-		bEnd = func(i int) bool { return i <= d.maxcol }
-		makePos = func(col, row int) Pos { return Pos{row, col} }
+		bEnd = func(i int) bool { return i <= d.MaxCol() }
+		makePos = func(col, row int) Pos { return Pos{Row: row, Col: col} }
 	case SOUTH:
-		a, aInc = d.maxcol, -1
+		a, aInc = d.MaxCol(), -1
 		aEnd = func(i int) bool { return i > 0 }
-		b, bInc = d.maxrow, -1
+		b, bInc = d.MaxRow(), -1
 		bEnd = func(i int) bool { return i > 0 }
-		makePos = func(row, col int) Pos { return Pos{row, col} }
+		makePos = func(row, col int) Pos { return Pos{Row: row, Col: col} }
 	case EAST:
-		a, aInc = d.maxrow, -1
+		a, aInc = d.MaxRow(), -1
 		aEnd = func(i int) bool { return i > 0 }
-		b, bInc = d.maxcol, -1
+		b, bInc = d.MaxCol(), -1
 		bEnd = func(i int) bool { return i > 0 }
-		makePos = func(col, row int) Pos { return Pos{row, col} }
+		makePos = func(col, row int) Pos { return Pos{Row: row, Col: col} }
 	default:
 		glog.Fatalf("cannot tilt %s yet", dir)
 	}
@@ -117,7 +114,7 @@ func (d *Dish) Tilt(dir CardinalDirection) {
 		next := a + bInc
 	FILL:
 		for fill := b; bEnd(fill); {
-			cell := d.C(makePos(fill, o)).(BaseCell)
+			cell := d.C(makePos(fill, o))
 			if cell.Symbol == "O" || cell.Symbol == "#" {
 				fill += bInc
 				if bInc > 0 {
@@ -129,7 +126,7 @@ func (d *Dish) Tilt(dir CardinalDirection) {
 			}
 			for check := next; bEnd(check); check += bInc {
 				d.checks++
-				cell = d.C(makePos(check, o)).(BaseCell)
+				cell = d.C(makePos(check, o))
 				if cell.Symbol == "." {
 					next = check
 					continue
@@ -140,8 +137,8 @@ func (d *Dish) Tilt(dir CardinalDirection) {
 					next = fill + bInc
 				} else {
 					glog.V(2).Infof("%d rolls to %d", makePos(check, o), makePos(fill, o))
-					d.SetC(makePos(fill, o), BaseCell{id: makePos(fill, o), Symbol: "O"})
-					d.SetC(makePos(check, o), BaseCell{id: makePos(check, o), Symbol: "."})
+					d.SetC(makePos(fill, o), BaseCell{ID: makePos(fill, o), Symbol: "O"})
+					d.SetC(makePos(check, o), BaseCell{ID: makePos(check, o), Symbol: "."})
 					next = check + bInc
 				}
 				continue FILL
@@ -181,11 +178,19 @@ func (d *Dish) Cycle(print ...bool) {
 	}
 }
 
+// Runs n spin cycles, using Key to skip over the cycles once the dish starts
+// repeating itself.
+func (d *Dish) Spin(n int) cycle.Loop {
+	loop := cycle.Run(n, d.Key, func() { d.Cycle() })
+	glog.Infof("Spun %d cycles: %s", n, loop)
+	return loop
+}
+
 func (d *Dish) Load() (sum int) {
-	for r := 1; r <= d.maxrow; r++ {
-		load := d.maxrow - r + 1 // 1 based, vs zero
-		for c := 1; c <= d.maxcol; c++ {
-			cell := d.C(Pos{r, c}).(BaseCell)
+	for r := 1; r <= d.MaxRow(); r++ {
+		load := d.MaxRow() - r + 1 // 1 based, vs zero
+		for c := 1; c <= d.MaxCol(); c++ {
+			cell := d.C(Pos{Row: r, Col: c})
 			if cell.Symbol == "O" {
 				sum += load
 			}
@@ -2,10 +2,11 @@ package day14
 
 import (
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 func Test_Sample(t *testing.T) {
@@ -67,16 +68,29 @@ func Test_Cycle3(t *testing.T) {
 	t.Logf("Took %d checks % 12d/% 12d from cache (%.2f%%)", dish.checks, dish.hit, dish.miss, float64((dish.hit)/(dish.hit+dish.miss))*100.0)
 }
 
-func noTest_Part2(t *testing.T) {
-	dish, err := NewDish("input")
+func Test_Sample_Part2(t *testing.T) {
+	dish, err := NewDish("sample")
 	require.NoError(t, err)
 
-	for i := 0; i < 1000000000; i++ {
-		dish.Cycle()
-		if i%1000 == 0 {
-			t.Logf("%s: cycle=% 12d; % 12d/% 12d tilts (%.2f%%) from cache", time.Now(), i, dish.hit, dish.miss, float64((dish.hit)/(dish.hit+dish.miss))*100.0)
-		}
+	loop := dish.Spin(1000000000)
+	assert.Equal(t, 3, loop.Prefix)
+	assert.Equal(t, 7, loop.Period)
+	assert.Equal(t, 64, dish.Load())
+
+	expected, err := NewDish("sample")
+	require.NoError(t, err)
+	for i := 0; i < loop.Index(1000000000); i++ {
+		expected.Cycle()
 	}
+	assert.True(t, expected.Equal(dish.Grid))
+}
+
+func Test_Part2(t *testing.T) {
+	dish, err := NewDish("input")
+	require.NoError(t, err)
+
+	loop := dish.Spin(1000000000)
+	t.Logf("Found %s", loop)
 
 	load := dish.Load()
 	t.Logf("Total Load: %d", load)
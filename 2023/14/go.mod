@@ -4,6 +4,7 @@ go 1.21.1
 
 require (
 	github.com/golang/glog v1.2.0
+	github.com/mattbnz/aoc/lib v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.8.4
 )
 
@@ -14,3 +15,5 @@ require (
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/mattbnz/aoc/lib => ../../lib
@@ -0,0 +1,25 @@
+package day14
+
+import (
+	. "github.com/mattbnz/aoc/lib/grid"
+	"github.com/mattbnz/aoc/lib/solver"
+)
+
+func init() {
+	solver.Register(2023, 14, 1, solver.Func(func(filename string) (any, error) {
+		dish, err := NewDish(filename)
+		if err != nil {
+			return nil, err
+		}
+		dish.Tilt(NORTH)
+		return dish.Load(), nil
+	}))
+	solver.Register(2023, 14, 2, solver.Func(func(filename string) (any, error) {
+		dish, err := NewDish(filename)
+		if err != nil {
+			return nil, err
+		}
+		dish.Spin(1000000000)
+		return dish.Load(), nil
+	}))
+}
@@ -2,6 +2,9 @@ package day15
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -9,28 +12,63 @@ import (
 	"github.com/golang/glog"
 )
 
-type Lens struct {
-	Label  string
-	Length int
+var ErrBadStep = errors.New("invalid step")
+
+type Op int
+
+const (
+	// Take the labelled lens out of its box.
+	Remove Op = iota
+	// Put the labelled lens in its box, or change its focal length.
+	Set
+)
+
+func (o Op) String() string {
+	if o == Remove {
+		return "-"
+	}
+	return "="
 }
 
-type Box struct {
-	Lenses []Lens
+// Step is one instruction from the initialization sequence, like "rn=1"
+// or "cm-".
+type Step struct {
+	// The step as written, which is what part 1 hashes.
+	Text   string
+	Label  string
+	Op     Op
+	Length int
 }
 
-func (b Box) Index(label string) int {
-	for n, l := range b.Lenses {
-		if l.Label == label {
-			return n
+func ParseStep(s string) (step Step, err error) {
+	step.Text = s
+	if label, found := strings.CutSuffix(s, "-"); found {
+		step.Label, step.Op = label, Remove
+	} else if label, lengthS, found := strings.Cut(s, "="); found {
+		step.Label, step.Op = label, Set
+		step.Length, err = strconv.Atoi(lengthS)
+		if err != nil || step.Length < 1 || step.Length > 9 {
+			return step, fmt.Errorf("%w %q: focal length %q is not 1-9", ErrBadStep, s, lengthS)
 		}
+	} else {
+		return step, fmt.Errorf("%w %q: no - or = operation", ErrBadStep, s)
 	}
-	return -1
+	if step.Label == "" {
+		return step, fmt.Errorf("%w %q: no label", ErrBadStep, s)
+	}
+	if strings.ContainsAny(step.Label, "-=") {
+		return step, fmt.Errorf("%w %q: label %q has more than one operation", ErrBadStep, s, step.Label)
+	}
+	return step, nil
 }
 
-type Manual struct {
-	Steps []string
+// The box the step's lens goes in.
+func (s Step) Box() int {
+	return HashInstruction(s.Label)
+}
 
-	Boxes []*Box
+type Manual struct {
+	Steps []Step
 }
 
 func NewManual(filename string) (m Manual, err error) {
@@ -40,23 +78,23 @@ func NewManual(filename string) (m Manual, err error) {
 	}
 	defer f.Close()
 
-	m.Boxes = make([]*Box, 256)
-	for b := range m.Boxes {
-		m.Boxes[b] = &Box{}
-	}
-
 	s := bufio.NewScanner(f)
 	for s.Scan() {
 		if s.Text() == "" {
 			return
 		}
-		ins := strings.Split(s.Text(), ",")
-		m.Steps = append(m.Steps, ins...)
+		for _, ins := range strings.Split(s.Text(), ",") {
+			step, err := ParseStep(ins)
+			if err != nil {
+				return m, fmt.Errorf("step %d: %w", len(m.Steps)+1, err)
+			}
+			m.Steps = append(m.Steps, step)
+		}
 	}
 	return
 }
 
-func (m Manual) HashInstruction(s string) (sum int) {
+func HashInstruction(s string) (sum int) {
 	for _, r := range s {
 		sum += int(r)
 		sum *= 17
@@ -65,52 +103,56 @@ func (m Manual) HashInstruction(s string) (sum int) {
 	return
 }
 
+func (m Manual) HashInstruction(s string) int {
+	return HashInstruction(s)
+}
+
 func (m Manual) Hash() (sum int) {
 	for _, s := range m.Steps {
-		h := m.HashInstruction(s)
-		glog.V(1).Infof("% 10s => %d", s, h)
+		h := HashInstruction(s.Text)
+		glog.V(1).Infof("% 10s => %d", s.Text, h)
 		sum += h
 	}
 	return
 }
 
-func (m Manual) Focus() (sum int) {
-	for _, s := range m.Steps {
-		if strings.HasSuffix(s, "-") {
-			// Replace
-			label := strings.TrimSuffix(s, "-")
-			boxIdx := m.HashInstruction(label)
-			box := m.Boxes[boxIdx]
-			lensIdx := box.Index(label)
-			if lensIdx != -1 {
-				box.Lenses = append(box.Lenses[:lensIdx], box.Lenses[lensIdx+1:]...)
-			}
-		} else {
-			label, lengthS, ok := strings.Cut(s, "=")
-			if !ok {
-				glog.Fatalf("Step %s: invalid format", s)
-			}
-			length, err := strconv.Atoi(lengthS)
-			if err != nil {
-				glog.Fatalf("Step %s: invalid format: %v", s, err)
-			}
-			boxIdx := m.HashInstruction(label)
-			box := m.Boxes[boxIdx]
-			lensIdx := box.Index(label)
-			if lensIdx == -1 {
-				box.Lenses = append(box.Lenses, Lens{Label: label, Length: length})
-			} else {
-				box.Lenses[lensIdx].Length = length
-			}
-		}
+// Returns the boxes after the first n steps (all of them if n is out of
+// range).
+func (m Manual) After(n int) *HASHMAP {
+	if n < 0 || n > len(m.Steps) {
+		n = len(m.Steps)
+	}
+	h := NewHASHMAP()
+	for _, s := range m.Steps[:n] {
+		h.Apply(s)
+	}
+	return h
+}
+
+// Writes the boxes after each of the first n steps (all of them if n is
+// out of range), the way the puzzle shows them:
+//
+//	After "rn=1":
+//	Box 0: [rn 1]
+func (m Manual) Trace(w io.Writer, n int) error {
+	if n < 0 || n > len(m.Steps) {
+		n = len(m.Steps)
+	}
+	bw := bufio.NewWriter(w)
+	h := NewHASHMAP()
+	for _, s := range m.Steps[:n] {
+		h.Apply(s)
+		fmt.Fprintf(bw, "After %q:\n%s\n", s.Text, h)
 	}
+	return bw.Flush()
+}
 
-	for boxNum, box := range m.Boxes {
-		for lensNum, l := range box.Lenses {
-			power := (boxNum + 1) * (lensNum + 1) * l.Length
-			glog.V(1).Infof("Lens %s at %d in box %d with length %d has power %d", l.Label, lensNum+1, boxNum+1, l.Length, power)
-			sum += power
+func (m Manual) Focus() int {
+	h := m.After(len(m.Steps))
+	for boxNum, box := range h.Boxes {
+		for lensNum, l := range box.Lenses() {
+			glog.V(1).Infof("Lens %s at %d in box %d with length %d has power %d", l.Label, lensNum+1, boxNum+1, l.Length, (boxNum+1)*(lensNum+1)*l.Length)
 		}
 	}
-	return
+	return h.Power()
 }
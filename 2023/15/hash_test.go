@@ -1,6 +1,7 @@
 package day15
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,99 @@ func Test_Sample_Part2(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, 145, manual.Focus())
+	assert.Equal(t, 145, manual.Focus(), "focusing again starts from empty boxes")
+}
+
+const sampleTrace = `After "rn=1":
+Box 0: [rn 1]
+
+After "cm-":
+Box 0: [rn 1]
+
+After "qp=3":
+Box 0: [rn 1]
+Box 1: [qp 3]
+
+After "cm=2":
+Box 0: [rn 1] [cm 2]
+Box 1: [qp 3]
+
+After "qp-":
+Box 0: [rn 1] [cm 2]
+
+After "pc=4":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4]
+
+After "ot=9":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4] [ot 9]
+
+After "ab=5":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4] [ot 9] [ab 5]
+
+After "pc-":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 9] [ab 5]
+
+After "pc=6":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 9] [ab 5] [pc 6]
+
+After "ot=7":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 7] [ab 5] [pc 6]
+
+`
+
+func Test_Trace(t *testing.T) {
+	manual, err := NewManual("sample")
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	require.NoError(t, manual.Trace(&sb, -1))
+	assert.Equal(t, sampleTrace, sb.String())
+
+	sb.Reset()
+	require.NoError(t, manual.Trace(&sb, 3))
+	assert.Equal(t, strings.Join(strings.SplitAfter(sampleTrace, "\n\n")[:3], ""), sb.String())
+
+	assert.Equal(t, "Box 0: [rn 1] [cm 2]\nBox 1: [qp 3]\n", manual.After(4).String())
+}
+
+func Test_ParseStep(t *testing.T) {
+	s, err := ParseStep("rn=1")
+	require.NoError(t, err)
+	assert.Equal(t, Step{Text: "rn=1", Label: "rn", Op: Set, Length: 1}, s)
+	assert.Equal(t, 0, s.Box())
+
+	s, err = ParseStep("cm-")
+	require.NoError(t, err)
+	assert.Equal(t, Step{Text: "cm-", Label: "cm", Op: Remove}, s)
+
+	for _, bad := range []string{"", "rn", "=1", "-", "rn=", "rn=x", "rn=10", "rn=0", "a=b=1", "a-=1"} {
+		_, err := ParseStep(bad)
+		assert.ErrorIs(t, err, ErrBadStep, bad)
+	}
+}
+
+func Test_Box(t *testing.T) {
+	b := NewBox()
+	b.Set("a", 1)
+	b.Set("b", 2)
+	b.Set("c", 3)
+	b.Set("a", 4)
+	b.Remove("b")
+	b.Remove("x")
+	b.Set("b", 5)
+	assert.Equal(t, []Lens{{"a", 4}, {"c", 3}, {"b", 5}}, b.Lenses())
+	l, found := b.Get("c")
+	assert.True(t, found)
+	assert.Equal(t, 3, l)
+	_, found = b.Get("x")
+	assert.False(t, found)
+	assert.Equal(t, "[a 4] [c 3] [b 5]", b.String())
 }
 func Test_Part2(t *testing.T) {
 	manual, err := NewManual("input")
@@ -0,0 +1,116 @@
+package day15
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+type Lens struct {
+	Label  string
+	Length int
+}
+
+// Box holds lenses in the order they were first put in, with each label
+// also indexed so that finding, replacing or removing a lens doesn't scan
+// the box.
+type Box struct {
+	order  *list.List
+	lenses map[string]*list.Element
+}
+
+func NewBox() *Box {
+	return &Box{order: list.New(), lenses: map[string]*list.Element{}}
+}
+
+// Returns the focal length of the lens labelled label, if it's in the box.
+func (b *Box) Get(label string) (int, bool) {
+	e, found := b.lenses[label]
+	if !found {
+		return 0, false
+	}
+	return e.Value.(*Lens).Length, true
+}
+
+// Replaces the lens labelled label where it is, or puts it in behind the
+// others if there isn't one.
+func (b *Box) Set(label string, length int) {
+	if e, found := b.lenses[label]; found {
+		e.Value.(*Lens).Length = length
+		return
+	}
+	b.lenses[label] = b.order.PushBack(&Lens{Label: label, Length: length})
+}
+
+// Takes the lens labelled label out, if it's in the box.
+func (b *Box) Remove(label string) {
+	if e, found := b.lenses[label]; found {
+		b.order.Remove(e)
+		delete(b.lenses, label)
+	}
+}
+
+func (b *Box) Len() int {
+	return b.order.Len()
+}
+
+// Returns the lenses, front to back.
+func (b *Box) Lenses() (rv []Lens) {
+	for e := b.order.Front(); e != nil; e = e.Next() {
+		rv = append(rv, *e.Value.(*Lens))
+	}
+	return
+}
+
+// Returns the lenses like the puzzle does, "[rn 1] [cm 2]".
+func (b *Box) String() string {
+	s := []string{}
+	for _, l := range b.Lenses() {
+		s = append(s, fmt.Sprintf("[%s %d]", l.Label, l.Length))
+	}
+	return strings.Join(s, " ")
+}
+
+// HASHMAP is the 256 boxes the steps arrange lenses in.
+type HASHMAP struct {
+	Boxes [256]*Box
+}
+
+func NewHASHMAP() *HASHMAP {
+	h := &HASHMAP{}
+	for n := range h.Boxes {
+		h.Boxes[n] = NewBox()
+	}
+	return h
+}
+
+func (h *HASHMAP) Apply(s Step) {
+	box := h.Boxes[s.Box()]
+	switch s.Op {
+	case Remove:
+		box.Remove(s.Label)
+	case Set:
+		box.Set(s.Label, s.Length)
+	}
+}
+
+// The focusing power of every lens, added up.
+func (h *HASHMAP) Power() (sum int) {
+	for boxNum, box := range h.Boxes {
+		for lensNum, l := range box.Lenses() {
+			sum += (boxNum + 1) * (lensNum + 1) * l.Length
+		}
+	}
+	return
+}
+
+// Returns the boxes with lenses in, a line each like "Box 0: [rn 1] [cm 2]".
+func (h *HASHMAP) String() string {
+	var sb strings.Builder
+	for n, box := range h.Boxes {
+		if box.Len() > 0 {
+			fmt.Fprintf(&sb, "Box %d: %s\n", n, box)
+		}
+	}
+	return sb.String()
+}
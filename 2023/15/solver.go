@@ -0,0 +1,20 @@
+package day15
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 15, 1, solver.Func(func(filename string) (any, error) {
+		manual, err := NewManual(filename)
+		if err != nil {
+			return nil, err
+		}
+		return manual.Hash(), nil
+	}))
+	solver.Register(2023, 15, 2, solver.Func(func(filename string) (any, error) {
+		manual, err := NewManual(filename)
+		if err != nil {
+			return nil, err
+		}
+		return manual.Focus(), nil
+	}))
+}
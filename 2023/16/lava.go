@@ -1,88 +1,111 @@
 package day16
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"runtime"
+	"sync"
 
 	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 type MirrorCell struct {
 	BaseCell
-
-	Cache  map[CardinalDirection]int
-	onPath map[CardinalDirection]bool
 }
 
-func (c *MirrorCell) New(s string, p Pos) Cell {
-	return &MirrorCell{
-		BaseCell: BaseCell{id: p, Symbol: s},
-		Cache:    map[CardinalDirection]int{},
-		onPath:   map[CardinalDirection]bool{},
-	}
+func (c *MirrorCell) New(s string, p Pos) *MirrorCell {
+	return &MirrorCell{BaseCell: BaseCell{ID: p, Symbol: s}}
 }
 
-func (c *MirrorCell) Beam(from CardinalDirection) (next []CardinalDirection, cacheKey CardinalDirection) {
-	cacheKey = from
-	var to CardinalDirection
-	if c.Symbol == "." {
-		to = from
-	} else if c.Symbol == "\\" {
+// Returns the headings a beam arriving with heading from leaves in.
+func (c *MirrorCell) Beam(from CardinalDirection) []CardinalDirection {
+	switch c.Symbol {
+	case "\\":
 		switch from {
 		case NORTH:
-			to = WEST
+			return []CardinalDirection{WEST}
 		case SOUTH:
-			to = EAST
+			return []CardinalDirection{EAST}
 		case EAST:
-			to = SOUTH
+			return []CardinalDirection{SOUTH}
 		case WEST:
-			to = NORTH
+			return []CardinalDirection{NORTH}
 		}
-	} else if c.Symbol == "/" {
+	case "/":
 		switch from {
 		case NORTH:
-			to = EAST
+			return []CardinalDirection{EAST}
 		case SOUTH:
-			to = WEST
+			return []CardinalDirection{WEST}
 		case EAST:
-			to = NORTH
+			return []CardinalDirection{NORTH}
 		case WEST:
-			to = SOUTH
+			return []CardinalDirection{SOUTH}
 		}
-	} else if c.Symbol == "|" {
-		switch from {
-		case EAST:
-			to = NORTH
-			next = append(next, SOUTH)
-			cacheKey = EAST
-		case WEST:
-			to = NORTH
-			next = append(next, SOUTH)
-			cacheKey = EAST
-		default:
-			to = from
+	case "|":
+		if from == EAST || from == WEST {
+			return []CardinalDirection{NORTH, SOUTH}
 		}
-	} else if c.Symbol == "-" {
-		switch from {
-		case NORTH:
-			to = WEST
-			next = append(next, EAST)
-			cacheKey = NORTH
-		case SOUTH:
-			to = WEST
-			next = append(next, EAST)
-			cacheKey = NORTH
-		default:
-			to = from
+	case "-":
+		if from == NORTH || from == SOUTH {
+			return []CardinalDirection{WEST, EAST}
+		}
+	}
+	return []CardinalDirection{from}
+}
+
+// Headings is the set of headings beams have entered a tile with.
+type Headings uint8
+
+func (h Headings) Has(d CardinalDirection) bool {
+	return h&(1<<d) != 0
+}
+
+func (h *Headings) Add(d CardinalDirection) {
+	*h |= 1 << d
+}
+
+// How many different headings are in the set.
+func (h Headings) Count() (n int) {
+	for _, d := range CardinalDirections {
+		if h.Has(d) {
+			n++
 		}
 	}
-	next = append(next, to)
 	return
 }
 
-type MirrorGrid struct {
-	Grid
+// Beams is where the light went after entering the grid at Entry, kept
+// apart from the grid so that many can be traced over one grid at once.
+type Beams struct {
+	Entry   Pos
+	Heading CardinalDirection
+
+	tiles [][]Headings
+}
 
-	beamCount int
+// The headings beams entered the tile at p with.
+func (b *Beams) At(p Pos) Headings {
+	return b.tiles[p.Row-1][p.Col-1]
+}
+
+// The number of tiles any beam passed through.
+func (b *Beams) Energized() (n int) {
+	for _, row := range b.tiles {
+		for _, h := range row {
+			if h != 0 {
+				n++
+			}
+		}
+	}
+	return
+}
+
+type MirrorGrid struct {
+	Grid[*MirrorCell]
 }
 
 func NewMirrorGrid(filename string) (m MirrorGrid, err error) {
@@ -96,93 +119,106 @@ func NewMirrorGrid(filename string) (m MirrorGrid, err error) {
 	return
 }
 
-func (g *MirrorGrid) Beam(id int, depth int, in Pos, heading CardinalDirection) int {
-	c := g.Grid.C(in).(*MirrorCell)
-	next, cacheKey := c.Beam(heading)
-
-	if length, found := c.Cache[cacheKey]; found {
-		if depth == 0 {
-			return length
-		}
-		return 0
-	}
+type beam struct {
+	at      Pos
+	heading CardinalDirection
+}
 
-	inc := 1
-	sym := "+"
-	if len(c.Cache) > 0 || len(c.onPath) > 0 {
-		inc = 0 // node already visited by another direction, don't count again.
-		sym = " "
+// Follows the light entering the grid at in, heading as given, through
+// every split until each beam leaves the grid or loops.
+func (g MirrorGrid) Beam(in Pos, heading CardinalDirection) *Beams {
+	b := &Beams{Entry: in, Heading: heading, tiles: make([][]Headings, g.MaxRow())}
+	for r := range b.tiles {
+		b.tiles[r] = make([]Headings, g.MaxCol())
 	}
-	glog.V(2).Infof("Beam % 2d (% 2d) %s => %s will go %s", id, depth, heading, in, next)
-
-	length := 0
-	wentAnywhere := false
-	for _, nHeading := range next {
-		if c.onPath[nHeading] {
-			glog.V(2).Infof("Beam % 2d can't go %s from %s (already on that path!)", id, nHeading, in)
+	todo := []beam{{in, heading}}
+	for len(todo) > 0 {
+		cur := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		h := &b.tiles[cur.at.Row-1][cur.at.Col-1]
+		if h.Has(cur.heading) {
 			continue
 		}
-		wentAnywhere = true
-		newNext, _, found := g.Next(c.id, nHeading)
-		if !found {
-			glog.V(2).Infof("Beam % 2d can't go %s from %s", id, nHeading, in)
-			continue
+		h.Add(cur.heading)
+		next := g.C(cur.at).Beam(cur.heading)
+		glog.V(2).Infof("Beam %s => %s will go %s", cur.heading, cur.at, next)
+		for _, nHeading := range next {
+			if np, _, found := g.Next(cur.at, nHeading); found {
+				todo = append(todo, beam{np, nHeading})
+			}
 		}
-		c.onPath[nHeading] = true
-		length += g.Beam(id, depth+1, newNext, nHeading)
-		delete(c.onPath, nHeading)
-	}
-	if wentAnywhere {
-		length += inc
 	}
-	glog.V(2).Infof("Beam % 2d (% 2d) %s => %s%s => %s returns %d", id, depth, heading, in, sym, next, length)
-	c.Cache[cacheKey] = length
-	return length
+	return b
 }
 
-// Returns a copy of this grid at the current minute (doesn't preserve past minutes)
-func (g *MirrorGrid) Copy() Grid {
-	ng := Grid{
-		maxrow: g.maxrow,
-		maxcol: g.maxcol,
-		c:      map[Pos]Cell{},
+// Entry is somewhere light can be shone in from the edge of the grid.
+type Entry struct {
+	Pos     Pos
+	Heading CardinalDirection
+}
+
+// Every tile on the edge of the grid, heading away from the edge.
+func (g MirrorGrid) Entries() (rv []Entry) {
+	for c := 1; c <= g.MaxCol(); c++ {
+		rv = append(rv, Entry{Pos{Row: 1, Col: c}, SOUTH}, Entry{Pos{Row: g.MaxRow(), Col: c}, NORTH})
 	}
-	for p, c := range g.c {
-		ng.c[p] = (&MirrorCell{}).New(c.(*MirrorCell).Symbol, p)
+	for r := 1; r <= g.MaxRow(); r++ {
+		rv = append(rv, Entry{Pos{Row: r, Col: 1}, EAST}, Entry{Pos{Row: r, Col: g.MaxCol()}, WEST})
 	}
-	return ng
+	return
 }
 
-func (g *MirrorGrid) Best() (best int) {
-	// North and South
-	for c := 1; c <= g.maxcol; c++ {
-		sG := MirrorGrid{Grid: g.Copy()}
-		n := sG.Beam(1, 1, Pos{1, c}, SOUTH)
-		if n > best {
-			glog.V(1).Infof("South from %d gives %d as new best", c, n)
-			best = n
+// Returns the beams from whichever entry energizes the most tiles (the
+// first in Entries order on a tie). The entries are traced by workers
+// goroutines (one per CPU if workers < 1), stopping early if ctx is done.
+func (g MirrorGrid) Best(ctx context.Context, workers int) (*Beams, error) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	entries := g.Entries()
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("empty grid has no entries")
+	}
+	energized := make([]int, len(entries))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				energized[i] = g.Beam(entries[i].Pos, entries[i].Heading).Energized()
+			}
+		}()
+	}
+	// Only set if ctx is done before every entry is handed out; after that
+	// the result is complete, whatever happens to ctx.
+	var err error
+feed:
+	for i := range entries {
+		if err = ctx.Err(); err != nil {
+			break
 		}
-		nG := MirrorGrid{Grid: g.Copy()}
-		n = nG.Beam(1, 1, Pos{g.maxrow, c}, NORTH)
-		if n > best {
-			glog.V(1).Infof("North from %d gives %d as new best", c, n)
-			best = n
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
 		}
 	}
-	// East and West
-	for r := 1; r <= g.maxrow; r++ {
-		eG := MirrorGrid{Grid: g.Copy()}
-		n := eG.Beam(1, 1, Pos{r, 1}, EAST)
-		if n > best {
-			glog.V(1).Infof("East from %d gives %d as new best", r, n)
-			best = n
-		}
-		wG := MirrorGrid{Grid: g.Copy()}
-		n = wG.Beam(1, 1, Pos{r, g.maxcol}, WEST)
-		if n > best {
-			glog.V(1).Infof("West from %d gives %d as new best", r, n)
-			best = n
+	close(next)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	best := 0
+	for i, n := range energized {
+		if n > energized[best] {
+			glog.V(1).Infof("%s from %s gives %d as new best", entries[i].Heading, entries[i].Pos, n)
+			best = i
 		}
 	}
-	return
+	return g.Beam(entries[best].Pos, entries[best].Heading), nil
 }
@@ -1,24 +1,41 @@
 package day16
 
 import (
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 func Test_Sample(t *testing.T) {
 	grid, err := NewMirrorGrid("sample")
 	require.NoError(t, err)
 	grid.PrintNumbered()
-	energized := grid.Beam(1, 1, Pos{1, 1}, EAST)
-	assert.Equal(t, 46, energized)
+	beams := grid.Beam(Pos{Row: 1, Col: 1}, EAST)
+	assert.Equal(t, 46, beams.Energized())
+	assert.Equal(t, Headings(0), beams.At(Pos{Row: 1, Col: 7}))
+	// The beam splits south at the first splitter, and comes back along
+	// the top row westwards into it.
+	assert.Equal(t, 2, beams.At(Pos{Row: 1, Col: 2}).Count())
+	assert.True(t, beams.At(Pos{Row: 1, Col: 2}).Has(EAST))
+	assert.True(t, beams.At(Pos{Row: 1, Col: 2}).Has(WEST))
+	assert.True(t, beams.At(Pos{Row: 2, Col: 2}).Has(SOUTH))
+	assert.False(t, beams.At(Pos{Row: 2, Col: 2}).Has(NORTH))
 }
 
 func Test_Part1(t *testing.T) {
 	grid, err := NewMirrorGrid("input")
 	require.NoError(t, err)
-	energized := grid.Beam(1, 1, Pos{1, 1}, EAST)
+	energized := grid.Beam(Pos{Row: 1, Col: 1}, EAST).Energized()
 	assert.Equal(t, 6921, energized)
 	t.Logf("Energized Tiles: %d", energized)
 }
@@ -26,14 +43,108 @@ func Test_Part1(t *testing.T) {
 func Test_Part2_Sample(t *testing.T) {
 	grid, err := NewMirrorGrid("sample")
 	require.NoError(t, err)
-	grid.PrintNumbered()
-	energized := grid.Best()
-	assert.Equal(t, 51, energized)
+	assert.Equal(t, 2*10+2*10, len(grid.Entries()))
+	best, err := grid.Best(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 51, best.Energized())
+	assert.Equal(t, Pos{Row: 1, Col: 4}, best.Entry)
+	assert.Equal(t, SOUTH, best.Heading)
+}
+
+// However many workers there are, every entry is tried and the same best
+// is found.
+func Test_Workers(t *testing.T) {
+	grid, err := NewMirrorGrid("sample")
+	require.NoError(t, err)
+	want, err := grid.Best(context.Background(), 1)
+	require.NoError(t, err)
+	for _, workers := range []int{2, 7, 64} {
+		best, err := grid.Best(context.Background(), workers)
+		require.NoError(t, err)
+		assert.Equal(t, want.Energized(), best.Energized(), workers)
+		assert.Equal(t, want.Entry, best.Entry, workers)
+		assert.Equal(t, want.Heading, best.Heading, workers)
+	}
+	for _, e := range grid.Entries() {
+		assert.LessOrEqual(t, grid.Beam(e.Pos, e.Heading).Energized(), want.Energized(), e)
+	}
+}
+
+func Test_Cancel(t *testing.T) {
+	grid, err := NewMirrorGrid("sample")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = grid.Best(ctx, 4)
+	assert.ErrorIs(t, err, context.Canceled)
 }
 
 func Test_Part2(t *testing.T) {
 	grid, err := NewMirrorGrid("input")
 	require.NoError(t, err)
-	energized := grid.Best()
-	t.Logf("Energized Tiles: %d", energized)
+	best, err := grid.Best(context.Background(), 0)
+	require.NoError(t, err)
+	t.Logf("Energized Tiles: %d going %s from %s", best.Energized(), best.Heading, best.Entry)
+}
+
+func Test_Render(t *testing.T) {
+	grid, err := NewMirrorGrid("sample")
+	require.NoError(t, err)
+	beams := grid.Beam(Pos{Row: 1, Col: 1}, EAST)
+	// As drawn in the puzzle.
+	assert.Equal(t, `>|<<<\....
+|v-.\^....
+.v...|->>>
+.v...v^.|.
+.v...v^...
+.v...v^..\
+.v../2\\..
+<->-/vv|..
+.|<<<2-|.\
+.v//.|.v..
+`, grid.Render(beams, Arrows))
+	assert.Equal(t, "121111....\n", grid.Render(beams, Counts)[:11])
+
+	var ansi strings.Builder
+	require.NoError(t, grid.WriteANSI(&ansi, beams, Arrows))
+	lines := strings.Split(ansi.String(), "\n")
+	assert.Equal(t, "E from 1,1: 46 tiles energized", lines[0])
+	assert.Equal(t, ansiReset+ansiYellow+">"+ansiReset+ansiBold+ansiRed+"|"+ansiReset+ansiYellow+"<<<"+
+		ansiReset+ansiBold+ansiRed+"\\"+ansiReset+ansiGray+"...."+ansiReset, lines[1])
+	plain := regexp.MustCompile("\033\\[[0-9;]*m").ReplaceAllString(strings.Join(lines[1:], "\n"), "")
+	assert.Equal(t, grid.Render(beams, Arrows), plain)
+}
+
+func Test_Image(t *testing.T) {
+	grid, err := NewMirrorGrid("sample")
+	require.NoError(t, err)
+	beams := grid.Beam(Pos{Row: 1, Col: 1}, EAST)
+	img := grid.Image(beams, 10)
+	assert.Equal(t, image.Rect(0, 0, 100, 100), img.Bounds())
+	assert.Equal(t, pngLit[0], img.RGBAAt(2, 2))  // 1,1 lit heading east
+	assert.Equal(t, pngBeam, img.RGBAAt(2, 5))    // with the beam across it
+	assert.Equal(t, pngLit[1], img.RGBAAt(12, 2)) // 1,2 lit both ways
+	assert.Equal(t, pngMirror, img.RGBAAt(15, 2)) // the | splitter
+	assert.Equal(t, pngDark, img.RGBAAt(92, 2))   // 1,10 is dark
+	assert.Equal(t, pngMirror, img.RGBAAt(50, 0)) // the \ mirror at 1,6
+	assert.Equal(t, pngMirror, img.RGBAAt(59, 9)) // and the other end of it
+
+	dir := t.TempDir()
+	best, err := grid.Best(context.Background(), 0)
+	require.NoError(t, err)
+	require.NoError(t, grid.WriteFile(filepath.Join(dir, "best.png"), best))
+	f, err := os.Open(filepath.Join(dir, "best.png"))
+	require.NoError(t, err)
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 80, 80), decoded.Bounds())
+
+	require.NoError(t, grid.WriteFile(filepath.Join(dir, "best.txt"), best))
+	text, err := os.ReadFile(filepath.Join(dir, "best.txt"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(text), "S from 1,4: 51 tiles energized\n"))
+	assert.Equal(t, best.Title()+"\n"+grid.Render(best, Arrows), string(text))
+
+	assert.Error(t, grid.WriteFile(filepath.Join(dir, "missing", "best.png"), best))
 }
@@ -0,0 +1,218 @@
+package day16
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/mattbnz/aoc/lib/grid"
+)
+
+// Style picks what is drawn on the energized tiles.
+type Style int
+
+const (
+	// The heading the beam went through an empty tile with, or how many
+	// headings if more than one did.
+	Arrows Style = iota
+	// How many headings beams went through every energized tile with.
+	Counts
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiGray   = "\033[90m"
+	ansiYellow = "\033[33m"
+	ansiRed    = "\033[31m"
+	ansiBold   = "\033[1m"
+)
+
+var arrows = map[CardinalDirection]string{NORTH: "^", EAST: ">", SOUTH: "v", WEST: "<"}
+
+// Returns what is drawn at p, and whether it is energized.
+func (g MirrorGrid) tile(b *Beams, p Pos, style Style) (string, bool) {
+	h := b.At(p)
+	sym := g.C(p).Symbol
+	switch {
+	case h == 0:
+		return sym, false
+	case style == Counts:
+		return fmt.Sprint(h.Count()), true
+	case sym != ".":
+		return sym, true
+	case h.Count() > 1:
+		return fmt.Sprint(h.Count()), true
+	}
+	for _, d := range CardinalDirections {
+		if h.Has(d) {
+			return arrows[d], true
+		}
+	}
+	return sym, true
+}
+
+// The line above a rendering, saying where the light came from.
+func (b *Beams) Title() string {
+	return fmt.Sprintf("%s from %s: %d tiles energized", b.Heading, b.Entry, b.Energized())
+}
+
+// Returns the grid with the beams drawn over it, as plain text.
+func (g MirrorGrid) Render(b *Beams, style Style) string {
+	var sb strings.Builder
+	for r := 1; r <= g.MaxRow(); r++ {
+		for c := 1; c <= g.MaxCol(); c++ {
+			s, _ := g.tile(b, Pos{Row: r, Col: c}, style)
+			sb.WriteString(s)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Writes the grid with the beams drawn over it in terminal colours:
+// energized tiles in yellow (mirrors and splitters in bold red), the rest
+// in gray.
+func (g MirrorGrid) WriteANSI(w io.Writer, b *Beams, style Style) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, b.Title())
+	for r := 1; r <= g.MaxRow(); r++ {
+		colour := ""
+		for c := 1; c <= g.MaxCol(); c++ {
+			p := Pos{Row: r, Col: c}
+			s, lit := g.tile(b, p, style)
+			want := ansiGray
+			if lit && g.C(p).Symbol != "." {
+				want = ansiBold + ansiRed
+			} else if lit {
+				want = ansiYellow
+			}
+			if want != colour {
+				bw.WriteString(ansiReset + want)
+				colour = want
+			}
+			bw.WriteString(s)
+		}
+		bw.WriteString(ansiReset + "\n")
+	}
+	return bw.Flush()
+}
+
+// Prints the beams in terminal colours.
+func (g MirrorGrid) PrintBeams(b *Beams, style Style) error {
+	return g.WriteANSI(os.Stdout, b, style)
+}
+
+var (
+	pngDark   = color.RGBA{30, 30, 30, 255}
+	pngMirror = color.RGBA{230, 230, 230, 255}
+	pngBeam   = color.RGBA{255, 80, 40, 255}
+	// Energized tiles, by how many headings went through them.
+	pngLit = []color.RGBA{
+		{90, 60, 0, 255},
+		{150, 100, 0, 255},
+		{200, 140, 0, 255},
+		{255, 190, 0, 255},
+	}
+)
+
+// Draws the grid with each tile as a scale pixel square (8 if scale is
+// less than 3). Energized tiles are brighter the more headings beams went
+// through them with, and each beam is drawn from the edge it came in by to
+// the middle of the tile, so the lines join up along its path.
+func (g MirrorGrid) Image(b *Beams, scale int) *image.RGBA {
+	if scale < 3 {
+		scale = 8
+	}
+	img := image.NewRGBA(image.Rect(0, 0, g.MaxCol()*scale, g.MaxRow()*scale))
+	mid := scale / 2
+	for r := 1; r <= g.MaxRow(); r++ {
+		for c := 1; c <= g.MaxCol(); c++ {
+			p := Pos{Row: r, Col: c}
+			x0, y0 := (c-1)*scale, (r-1)*scale
+			h := b.At(p)
+			bg := pngDark
+			if h != 0 {
+				bg = pngLit[h.Count()-1]
+			}
+			for y := 0; y < scale; y++ {
+				for x := 0; x < scale; x++ {
+					img.SetRGBA(x0+x, y0+y, bg)
+				}
+			}
+			line := func(x1, y1, x2, y2 int, col color.RGBA) {
+				steps := max(abs(x2-x1), abs(y2-y1))
+				for i := 0; i <= steps; i++ {
+					img.SetRGBA(x0+x1+(x2-x1)*i/max(steps, 1), y0+y1+(y2-y1)*i/max(steps, 1), col)
+				}
+			}
+			for _, d := range CardinalDirections {
+				if !h.Has(d) {
+					continue
+				}
+				switch d {
+				case NORTH:
+					line(mid, scale-1, mid, mid, pngBeam)
+				case SOUTH:
+					line(mid, 0, mid, mid, pngBeam)
+				case EAST:
+					line(0, mid, mid, mid, pngBeam)
+				case WEST:
+					line(scale-1, mid, mid, mid, pngBeam)
+				}
+			}
+			switch g.C(p).Symbol {
+			case "/":
+				line(0, scale-1, scale-1, 0, pngMirror)
+			case "\\":
+				line(0, 0, scale-1, scale-1, pngMirror)
+			case "|":
+				line(mid, 0, mid, scale-1, pngMirror)
+			case "-":
+				line(0, mid, scale-1, mid, pngMirror)
+			}
+		}
+	}
+	return img
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Writes the beams as a PNG image, see Image.
+func (g MirrorGrid) WritePNG(w io.Writer, b *Beams, scale int) error {
+	return png.Encode(w, g.Image(b, scale))
+}
+
+// Writes the beams to filename: a PNG if it ends in .png, plain text if it
+// ends in .txt, otherwise terminal text with arrows. "-" prints them.
+func (g MirrorGrid) WriteFile(filename string, b *Beams) error {
+	if filename == "-" {
+		return g.PrintBeams(b, Arrows)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(filename) {
+	case ".png":
+		err = g.WritePNG(f, b, 0)
+	case ".txt":
+		_, err = fmt.Fprintf(f, "%s\n%s", b.Title(), g.Render(b, Arrows))
+	default:
+		err = g.WriteANSI(f, b, Arrows)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
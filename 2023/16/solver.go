@@ -0,0 +1,49 @@
+package day16
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/mattbnz/aoc/lib/grid"
+	"github.com/mattbnz/aoc/lib/solver"
+)
+
+// beamSolver answers a part with how many tiles some beams energize, and
+// can render those beams with MirrorGrid.WriteFile.
+type beamSolver func(g MirrorGrid) (*Beams, error)
+
+func (f beamSolver) trace(filename string) (MirrorGrid, *Beams, error) {
+	grid, err := NewMirrorGrid(filename)
+	if err != nil {
+		return grid, nil, err
+	}
+	b, err := f(grid)
+	return grid, b, err
+}
+
+func (f beamSolver) Solve(filename string) (string, error) {
+	_, b, err := f.trace(filename)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(b.Energized()), nil
+}
+
+func (f beamSolver) Render(filename, out string) error {
+	grid, b, err := f.trace(filename)
+	if err != nil {
+		return err
+	}
+	return grid.WriteFile(out, b)
+}
+
+var _ solver.Renderer = beamSolver(nil)
+
+func init() {
+	solver.Register(2023, 16, 1, beamSolver(func(g MirrorGrid) (*Beams, error) {
+		return g.Beam(Pos{Row: 1, Col: 1}, EAST), nil
+	}))
+	solver.Register(2023, 16, 2, beamSolver(func(g MirrorGrid) (*Beams, error) {
+		return g.Best(context.Background(), 0)
+	}))
+}
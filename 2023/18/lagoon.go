@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 const TRENCH = "#"
@@ -27,24 +29,19 @@ type LagoonCell struct {
 	visited bool
 }
 
+func (c *LagoonCell) New(s string, p Pos) *LagoonCell {
+	return &LagoonCell{BaseCell: BaseCell{ID: p, Symbol: s}}
+}
+
 type Lagoon struct {
-	FlexGrid
+	FlexGrid[*LagoonCell]
 
 	Digger Pos
 }
 
 // Moves l.Digger in the specified direction.
 func (l *Lagoon) Move(dir CardinalDirection) {
-	switch dir {
-	case NORTH:
-		l.Digger = Pos{l.Digger.row - 1, l.Digger.col}
-	case EAST:
-		l.Digger = Pos{l.Digger.row, l.Digger.col + 1}
-	case SOUTH:
-		l.Digger = Pos{l.Digger.row + 1, l.Digger.col}
-	case WEST:
-		l.Digger = Pos{l.Digger.row, l.Digger.col - 1}
-	}
+	l.Digger = l.Digger.Move(dir)
 }
 
 // Digs a trench of length in dir from l.Digger
@@ -57,10 +54,81 @@ func (l *Lagoon) Trench(dir CardinalDirection, length int) {
 
 // Digs a hole at l.Digger
 func (l *Lagoon) Dig() {
-	l.SetC(l.Digger, &LagoonCell{BaseCell: BaseCell{id: l.Digger, Symbol: TRENCH}})
+	l.SetC(l.Digger, &LagoonCell{BaseCell: BaseCell{ID: l.Digger, Symbol: TRENCH}})
 }
 
-func NewLagoon(filename string) (l Lagoon, err error) {
+// How each line of the dig plan is read.
+type PlanMode int
+
+const (
+	// Direction and length from the first two columns (part 1).
+	PLAN_DIRECTION PlanMode = iota
+	// Direction and length decoded from the colour column (part 2).
+	PLAN_COLOUR
+)
+
+// Last hex digit of the colour gives the direction.
+var colourDir = map[byte]CardinalDirection{
+	'0': EAST,
+	'1': SOUTH,
+	'2': WEST,
+	'3': NORTH,
+}
+
+type DigStep struct {
+	Dir    CardinalDirection
+	Length int
+}
+
+func (s DigStep) String() string {
+	return fmt.Sprintf("%s %d", s.Dir, s.Length)
+}
+
+func NewDigStep(line string, mode PlanMode) (step DigStep, err error) {
+	parts := strings.SplitN(line, " ", -1)
+	if len(parts) != 3 {
+		err = fmt.Errorf("bad input line: %s", line)
+		return
+	}
+	if mode == PLAN_COLOUR {
+		colour := strings.Trim(parts[2], "(#)")
+		if len(colour) != 6 {
+			err = fmt.Errorf("bad colour: %s", parts[2])
+			return
+		}
+		dir, ok := colourDir[colour[5]]
+		if !ok {
+			err = fmt.Errorf("bad colour direction: %s", parts[2])
+			return
+		}
+		length, lErr := strconv.ParseInt(colour[:5], 16, 0)
+		if lErr != nil {
+			err = fmt.Errorf("bad colour length %s: %w", parts[2], lErr)
+			return
+		}
+		return DigStep{dir, int(length)}, nil
+	}
+	dir, ok := digDir[parts[0]]
+	if !ok {
+		err = fmt.Errorf("bad dig direction: %s", parts[0])
+		return
+	}
+	length, lErr := strconv.Atoi(parts[1])
+	if lErr != nil {
+		err = lErr
+		return
+	}
+	// Colour ignored for part1.
+	return DigStep{dir, length}, nil
+}
+
+// DigPlan holds the steps of the plan without digging them, so the volume
+// can be calculated from the corners of the trench for part 2 distances.
+type DigPlan struct {
+	Steps []DigStep
+}
+
+func NewDigPlan(filename string, mode PlanMode) (plan DigPlan, err error) {
 	f, err := os.OpenFile(filename, os.O_RDONLY, 0)
 	if err != nil {
 		return
@@ -68,32 +136,80 @@ func NewLagoon(filename string) (l Lagoon, err error) {
 	defer f.Close()
 
 	s := bufio.NewScanner(f)
-
-	origin := Pos{1, 1}
-	l.FlexGrid = EmptyGrid()
-	l.Digger = origin
-	l.Dig()
 	for s.Scan() {
 		if s.Text() == "" {
-			return
+			break
 		}
-		parts := strings.SplitN(s.Text(), " ", -1)
-		if len(parts) != 3 {
-			err = fmt.Errorf("bad input line: %s", s.Text())
+		step, sErr := NewDigStep(s.Text(), mode)
+		if sErr != nil {
+			err = sErr
 			return
 		}
-		dir, ok := digDir[parts[0]]
-		if !ok {
-			err = fmt.Errorf("bad dig direction: %s", parts[0])
-			return
-		}
-		length, lErr := strconv.Atoi(parts[1])
-		if lErr != nil {
-			err = lErr
-			return
+		plan.Steps = append(plan.Steps, step)
+	}
+	return
+}
+
+// Returns the corners of the trench, starting (and ending) at the origin.
+func (p DigPlan) Vertices() (rv []Pos) {
+	pos := Pos{Row: 1, Col: 1}
+	rv = append(rv, pos)
+	for _, step := range p.Steps {
+		switch step.Dir {
+		case NORTH:
+			pos.Row -= step.Length
+		case SOUTH:
+			pos.Row += step.Length
+		case EAST:
+			pos.Col += step.Length
+		case WEST:
+			pos.Col -= step.Length
 		}
-		// Colour ignored for part1.
-		l.Trench(dir, length)
+		rv = append(rv, pos)
+	}
+	return
+}
+
+func (p DigPlan) TrenchLength() (rv int) {
+	for _, step := range p.Steps {
+		rv += step.Length
+	}
+	return
+}
+
+// Returns the volume of the lagoon, same as Lagoon.Volume but without
+// digging out each cell.
+//
+// The shoelace formula gives the area inside the line through the centre of
+// each trench cell, Pick's theorem turns that into the count of cells inside
+// the trench, and then the trench cells themselves are added on.
+func (p DigPlan) Volume() int {
+	v := p.Vertices()
+	if v[len(v)-1] != v[0] {
+		glog.Warningf("Plan ends at %s not %s, closing the loop", v[len(v)-1], v[0])
+	}
+	area2 := 0
+	for i := range v {
+		a, b := v[i], v[(i+1)%len(v)]
+		area2 += a.Row*b.Col - b.Row*a.Col
+	}
+	boundary := p.TrenchLength()
+	interior := (Abs(area2)-boundary)/2 + 1
+	return interior + boundary
+}
+
+func NewLagoon(filename string) (l Lagoon, err error) {
+	plan, err := NewDigPlan(filename, PLAN_DIRECTION)
+	if err != nil {
+		return
+	}
+
+	origin := Pos{Row: 1, Col: 1}
+	l.FlexGrid = NewFlexGrid[*LagoonCell]()
+	l.Digger = origin
+	l.Dig()
+	for _, step := range plan.Steps {
+		l.Trench(step.Dir, step.Length)
 	}
 	if l.Digger != origin {
 		glog.Warningf("Digger stopped at %s not %s after all input lines!!", l.Digger, origin)
@@ -102,11 +218,11 @@ func NewLagoon(filename string) (l Lagoon, err error) {
 }
 
 func (c *Lagoon) TrenchLength() (rv int) {
-	c.Each(func(_ Pos, c Cell) bool {
+	c.Each(func(_ Pos, c *LagoonCell) bool {
 		if c == nil {
 			return true
 		}
-		if c.(*LagoonCell).Symbol == TRENCH {
+		if c.Symbol == TRENCH {
 			rv++
 		}
 		return true
@@ -117,11 +233,10 @@ func (c *Lagoon) TrenchLength() (rv int) {
 func (c *Lagoon) C(p Pos) *LagoonCell {
 	rv := c.FlexGrid.C(p)
 	if rv == nil {
-		lc := &LagoonCell{BaseCell: BaseCell{id: p, Symbol: "."}}
-		c.FlexGrid.c[p] = lc
-		return lc
+		rv = &LagoonCell{BaseCell: BaseCell{ID: p, Symbol: "."}}
+		c.FlexGrid.SetC(p, rv)
 	}
-	return rv.(*LagoonCell)
+	return rv
 }
 
 func (c *Lagoon) Visit(p Pos) {
@@ -149,21 +264,21 @@ func (c *Lagoon) Visit(p Pos) {
 
 func (c *Lagoon) VisitAll() {
 	// Visit (recursively from every outside cell inwards)
-	for col := c.mincol; col <= c.maxcol; col++ {
-		c.Visit(Pos{c.minrow, col})
-		c.Visit(Pos{c.maxrow, col})
+	for col := c.MinCol(); col <= c.MaxCol(); col++ {
+		c.Visit(Pos{Row: c.MinRow(), Col: col})
+		c.Visit(Pos{Row: c.MaxRow(), Col: col})
 	}
-	for row := c.minrow; row <= c.maxrow; row++ {
-		c.Visit(Pos{row, c.mincol})
-		c.Visit(Pos{row, c.maxcol})
+	for row := c.MinRow(); row <= c.MaxRow(); row++ {
+		c.Visit(Pos{Row: row, Col: c.MinCol()})
+		c.Visit(Pos{Row: row, Col: c.MaxCol()})
 	}
 }
 
 func (c *Lagoon) RowVolume(row int) (rv int) {
 	c.VisitAll()
 	s := ""
-	for col := c.mincol; col <= c.maxcol; col++ {
-		cell := c.C(Pos{row, col})
+	for col := c.MinCol(); col <= c.MaxCol(); col++ {
+		cell := c.C(Pos{Row: row, Col: col})
 		if cell.Symbol == TRENCH || cell.Symbol == DEFAULT {
 			rv++
 		}
@@ -175,7 +290,7 @@ func (c *Lagoon) RowVolume(row int) (rv int) {
 
 func (c *Lagoon) Volume() (rv int) {
 	c.VisitAll()
-	c.Each(func(p Pos, _ Cell) bool {
+	c.Each(func(p Pos, _ *LagoonCell) bool {
 		cell := c.C(p)
 		if cell.Symbol == TRENCH || cell.Symbol == DEFAULT {
 			rv++
@@ -5,8 +5,11 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/mattbnz/aoc/lib/answers/answerstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	. "github.com/mattbnz/aoc/lib/grid"
 )
 
 func Test_Sample(t *testing.T) {
@@ -22,7 +25,7 @@ func Test_Sample(t *testing.T) {
 }
 
 func testLagoon(s string) (l Lagoon) {
-	l.FlexGrid = NewGridFromScanner[BaseCell](bufio.NewScanner(bytes.NewBufferString(s)))
+	l.FlexGrid = NewFlexGridFromScanner[*LagoonCell](bufio.NewScanner(bytes.NewBufferString(s)))
 	return
 }
 
@@ -31,9 +34,7 @@ func Test_Part1(t *testing.T) {
 	require.NoError(t, err)
 
 	volume := lagoon.Volume()
-	assert.Less(t, volume, 100724)   // First Guess
-	assert.Less(t, volume, 93240)    // Second Guess
-	assert.Greater(t, volume, 90818) // Third Guess
+	answerstest.Assert(t, 1, volume)
 	t.Logf("Lagon Volume: %d", volume)
 }
 
@@ -41,14 +42,58 @@ func Test_Debug(t *testing.T) {
 	lagoon, err := NewLagoon("input")
 	require.NoError(t, err)
 	v := 0
-	for row := lagoon.minrow; row <= lagoon.maxrow; row++ {
+	for row := lagoon.MinRow(); row <= lagoon.MaxRow(); row++ {
 		v += lagoon.RowVolume(row)
 	}
 	volume := lagoon.Volume()
 	assert.Equal(t, v, volume)
 	volume = v
-	assert.Less(t, volume, 100724)   // First Guess
-	assert.Less(t, volume, 93240)    // Second Guess
-	assert.Greater(t, volume, 90818) // Third Guess
+	answerstest.Assert(t, 1, volume)
 	t.Logf("Lagon Volume: %d", volume)
 }
+
+func Test_DigStep(t *testing.T) {
+	step, err := NewDigStep("R 6 (#70c710)", PLAN_DIRECTION)
+	require.NoError(t, err)
+	assert.Equal(t, DigStep{EAST, 6}, step)
+	step, err = NewDigStep("R 6 (#70c710)", PLAN_COLOUR)
+	require.NoError(t, err)
+	assert.Equal(t, DigStep{EAST, 461937}, step)
+	step, err = NewDigStep("U 2 (#7a21e3)", PLAN_COLOUR)
+	require.NoError(t, err)
+	assert.Equal(t, DigStep{NORTH, 500254}, step)
+
+	_, err = NewDigStep("U 2 (#7a21e4)", PLAN_COLOUR)
+	assert.Error(t, err)
+	_, err = NewDigStep("U 2", PLAN_DIRECTION)
+	assert.Error(t, err)
+}
+
+func Test_Sample_Plan(t *testing.T) {
+	lagoon, err := NewLagoon("sample")
+	require.NoError(t, err)
+	plan, err := NewDigPlan("sample", PLAN_DIRECTION)
+	require.NoError(t, err)
+
+	assert.Equal(t, lagoon.TrenchLength(), plan.TrenchLength())
+	assert.Equal(t, lagoon.Volume(), plan.Volume())
+	assert.Equal(t, 62, plan.Volume())
+}
+
+func Test_Sample_Part2(t *testing.T) {
+	plan, err := NewDigPlan("sample", PLAN_COLOUR)
+	require.NoError(t, err)
+	assert.Equal(t, 952408144115, plan.Volume())
+}
+
+func Test_Part2(t *testing.T) {
+	lagoon, err := NewLagoon("input")
+	require.NoError(t, err)
+	plan, err := NewDigPlan("input", PLAN_DIRECTION)
+	require.NoError(t, err)
+	assert.Equal(t, lagoon.Volume(), plan.Volume())
+
+	plan, err = NewDigPlan("input", PLAN_COLOUR)
+	require.NoError(t, err)
+	t.Logf("Lagoon Volume: %d", plan.Volume())
+}
@@ -0,0 +1,20 @@
+package day18
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 18, 1, solver.Func(func(filename string) (any, error) {
+		plan, err := NewDigPlan(filename, PLAN_DIRECTION)
+		if err != nil {
+			return nil, err
+		}
+		return plan.Volume(), nil
+	}))
+	solver.Register(2023, 18, 2, solver.Func(func(filename string) (any, error) {
+		plan, err := NewDigPlan(filename, PLAN_COLOUR)
+		if err != nil {
+			return nil, err
+		}
+		return plan.Volume(), nil
+	}))
+}
@@ -0,0 +1,21 @@
+package day19
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 19, 1, solver.Func(func(filename string) (any, error) {
+		heap, err := NewHeap(filename)
+		if err != nil {
+			return nil, err
+		}
+		heap.SortParts()
+		return heap.Sum("A"), nil
+	}))
+	solver.Register(2023, 19, 2, solver.Func(func(filename string) (any, error) {
+		heap, err := NewHeap(filename)
+		if err != nil {
+			return nil, err
+		}
+		return heap.Combinations(), nil
+	}))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/mattbnz/aoc/lib/graph"
 )
 
 type Part map[string]int
@@ -42,10 +43,6 @@ type Rule struct {
 	op   string
 	val  int
 	dest string
-
-	DestW          *Workflow
-	Size           int64
-	RemainingSpace map[string]int64
 }
 
 func (r Rule) IsDefault() bool {
@@ -63,7 +60,6 @@ var ruleRegexp = regexp.MustCompile(`^([a-z]+)([><])(\d+):([a-zAR]+)$`)
 
 func NewRule(s string) (rv *Rule) {
 	rv = &Rule{}
-	rv.RemainingSpace = make(map[string]int64)
 	if !strings.Contains(s, ":") {
 		rv.dest = s
 		return
@@ -92,8 +88,6 @@ type Workflow struct {
 	Default string
 
 	Queue []*Part
-
-	Result []*big.Int
 }
 
 func (w *Workflow) Append(p *Part) {
@@ -218,204 +212,183 @@ func (h *Heap) Sum(workflow string) (rv int) {
 	return
 }
 
-// Node represents a branch from a workflow rule match.
-type Node struct {
-	// Workflow and rule index that this node was created from
-	W *Workflow
-	R int
-}
-
-func (h *Heap) BuildGraph() {
-	for _, w := range h.Workflows {
-		for _, r := range w.Rules {
-			r.DestW = h.Workflows[r.dest]
+// Returns the workflows as a graph, walked from "in", with an edge for
+// each rule labelled by its condition.
+func (h *Heap) Graph() *graph.Graph {
+	g := graph.New("workflows")
+	seen := map[string]bool{}
+	var walk func(w *Workflow)
+	walk = func(w *Workflow) {
+		if w == nil || seen[w.Name] {
+			return
 		}
-	}
-	for _, w := range h.Workflows {
-		rules := []Rule{}
+		seen[w.Name] = true
+		g.AddNode(w.Name, "")
 		for _, r := range w.Rules {
-			rules = append(rules, *r)
+			walk(h.Workflows[r.dest])
 		}
-		rSizes, _ := calcAttrSize(rules, false)
-		aSizes := map[string]int64{"x": 4000, "m": 4000, "a": 4000, "s": 4000}
-		for n, size := range rSizes {
-			w.Rules[n].Size = size
-			aSizes[w.Rules[n].attr] -= size
-			for k, v := range aSizes {
-				w.Rules[n].RemainingSpace[k] = v
+	}
+	walk(h.Workflows["in"])
+	names := []string{}
+	for name := range h.Workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		walk(h.Workflows[name])
+	}
+	for _, n := range g.Nodes {
+		for _, r := range h.Workflows[n.ID].Rules {
+			cond := "else"
+			if !r.IsDefault() {
+				cond = fmt.Sprintf("%s%s%d", r.attr, r.op, r.val)
 			}
+			g.AddEdge(n.ID, r.dest, cond)
 		}
-		/*if len(w.Rules) > 0 {
-			defIdx := len(w.Rules) - 1
-			for a, size := range aSizes {
-				w.Rules[defIdx].RemainingSpace[a] = 4000 - size
-			}
-		}*/
-		/*		for _, a := range []string{"x", "m", "a", "s"} {
-					aR := []*Rule{}
-					for _, r := range w.Rules {
-						if r.attr != a {
-							continue
-						}
-						aR = append(aR, r)
-					}
-					sort.Slice(aR, func(i, j int) bool {
-						return aR[i].val < aR[j].val
-					})
-					aSize := int64(4000)
-					for n, r := range aR {
-						switch r.op {
-						case ">":
-							max := 4000
-							if n < len(aR)-1 {
-								max = aR[n+1].val
-							}
-							r.Size = int64(max - (r.val + 1))
-						case "<":
-							bottom := 0
-							if n > 0 {
-								bottom = aR[n-1].val
-							}
-							r.Size = int64(r.val - 1 - bottom)
-						default:
-							glog.Fatalf("bad rule (%s) in %s", r, w)
-						}
-						aSize -= r.Size
-					}
-					defaultSize += aSize
-				}
-				if len(w.Rules) > 1 {
-					w.Rules[len(w.Rules)-1].Size = defaultSize
-				} else if len(w.Rules) == 1 {
-					w.Rules[0].Size = 4000
-				}*/
 	}
+	return g
 }
 
-func calcAttrSize(rules []Rule, useLimits bool) (rSizes []int64, aSizes map[string]int64) {
-	aSizes = make(map[string]int64)
-
-	for _, a := range []string{"x", "m", "a", "s"} {
-		clamp := 0
-		aR := []*Rule{}
-		for n, r := range rules {
-			if useLimits && r.RemainingSpace[a] != 0 {
-				if clamp == 0 {
-					clamp = int(r.RemainingSpace[a])
-				} else {
-					//glog.Infof("multiple defaults for " + a)
-					clamp = Min(clamp, int(r.RemainingSpace[a]))
-				}
-			}
-			if r.attr != a {
-				continue
-			}
-			aR = append(aR, &rules[n])
-		}
-		sort.Slice(aR, func(i, j int) bool {
-			return aR[i].val < aR[j].val
-		})
-		sum := int64(0)
-		for n, r := range aR {
-			switch r.op {
-			case ">":
-				max := 4000
-				if n < len(aR)-1 {
-					max = aR[n+1].val
-				}
-				r.Size = int64(max - (r.val))
-			case "<":
-				bottom := 0
-				if n > 0 {
-					bottom = aR[n-1].val
-				}
-				r.Size = int64(r.val - 1 - bottom)
-			default:
-				glog.Fatalf("bad rule (%s)", r)
-			}
-			sum += r.Size
-		}
-		if clamp == 0 {
-			aSizes[a] = sum
-		} else if sum == 0 {
-			aSizes[a] = int64(clamp)
-		} else {
-			aSizes[a] = int64(Min(int(sum), clamp))
-		}
+// Span is an inclusive range of attribute values.
+type Span struct {
+	Lo, Hi int
+}
+
+func (s Span) Empty() bool {
+	return s.Hi < s.Lo
+}
+
+func (s Span) Len() int64 {
+	if s.Empty() {
+		return 0
 	}
+	return int64(s.Hi - s.Lo + 1)
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%d..%d", s.Lo, s.Hi)
+}
 
-	rSizes = make([]int64, len(rules))
-	for n, r := range rules {
-		rSizes[n] = r.Size
+var Attrs = []string{"x", "m", "a", "s"}
+
+// Box is the set of parts whose attributes all fall in the given Spans,
+// indexed in the same order as Attrs.
+type Box [4]Span
+
+func NewBox(lo, hi int) (b Box) {
+	for n := range b {
+		b[n] = Span{lo, hi}
 	}
 	return
 }
 
-type Path struct {
-	N string
-	R *Rule
+func attrIdx(attr string) int {
+	for n, a := range Attrs {
+		if a == attr {
+			return n
+		}
+	}
+	glog.Fatalf("bad attribute: %s", attr)
+	return -1
+}
+
+func (b Box) Get(attr string) Span {
+	return b[attrIdx(attr)]
 }
 
-func (h *Heap) Combinations() *big.Int {
-	in := h.Workflows["in"]
-	in.walk([]Path{})
+func (b Box) With(attr string, s Span) Box {
+	b[attrIdx(attr)] = s
+	return b
+}
 
-	sum := big.NewInt(0)
-	A := h.Workflows["A"]
-	for _, r := range A.Result {
-		sum = sum.Add(sum, r)
-		glog.Infof("+ %s", r)
+func (b Box) Empty() bool {
+	for _, s := range b {
+		if s.Empty() {
+			return true
+		}
 	}
-	return sum
+	return false
 }
 
-var Neg1 = big.NewInt(-1)
+func (b Box) Contains(p Part) bool {
+	for n, a := range Attrs {
+		if p[a] < b[n].Lo || p[a] > b[n].Hi {
+			return false
+		}
+	}
+	return true
+}
 
-func (w *Workflow) walk(path []Path) *big.Int {
-	rules := []Rule{}
-	for n := range path {
-		rules = append(rules, *path[n].R)
+func (b Box) Volume() *big.Int {
+	rv := big.NewInt(1)
+	for _, s := range b {
+		rv.Mul(rv, big.NewInt(s.Len()))
 	}
-	_, aSizes := calcAttrSize(rules, true)
-
-	if len(w.Rules) == 0 {
-		product := big.NewInt(1)
-		dbg := []string{}
-		for a, s := range aSizes {
-			if s == 0 {
-				s = 4000
-			}
-			n := big.NewInt(s)
-			product = product.Mul(product, n)
-			dbg = append(dbg, fmt.Sprintf("%s:%d", a, s))
-		}
-		path = append(path, Path{N: w.Name})
-		glog.Infof("         %s", strings.Join(dbg, " x "))
-		LogPath(w, path, product)
-		w.Result = append(w.Result, product)
-		//glog.Infof("%#v", rSizes)
-		//glog.Infof("%#v", aSizes)
-		return Neg1
+	return rv
+}
+
+func (b Box) String() string {
+	s := []string{}
+	for n, a := range Attrs {
+		s = append(s, fmt.Sprintf("%s=%s", a, b[n]))
 	}
+	return "{" + strings.Join(s, ",") + "}"
+}
 
-	rv := big.NewInt(0)
+// Splits b into the parts that match the rule and the rest that carry on to
+// the next rule. Either may be Empty.
+func (r Rule) Split(b Box) (match, rest Box) {
+	if r.IsDefault() {
+		return b, b.With(Attrs[0], Span{1, 0}) // nothing left over
+	}
+	s := b.Get(r.attr)
+	switch r.op {
+	case "<":
+		match = b.With(r.attr, Span{s.Lo, Min(s.Hi, r.val-1)})
+		rest = b.With(r.attr, Span{Max(s.Lo, r.val), s.Hi})
+	case ">":
+		match = b.With(r.attr, Span{Max(s.Lo, r.val+1), s.Hi})
+		rest = b.With(r.attr, Span{s.Lo, Min(s.Hi, r.val)})
+	default:
+		glog.Fatalf("bad rule (%s), cannot split %s", r, b)
+	}
+	return
+}
+
+// Returns the boxes of parts (of the full 1-4000 range) that end up in A.
+func (h *Heap) AcceptedBoxes() []Box {
+	return h.flow("in", NewBox(1, 4000), nil)
+}
+
+func (h *Heap) flow(workflow string, b Box, accepted []Box) []Box {
+	if workflow == "A" {
+		glog.V(1).Infof("Accepted %s", b)
+		return append(accepted, b)
+	} else if workflow == "R" {
+		return accepted
+	}
+	w, found := h.Workflows[workflow]
+	if !found {
+		glog.Fatalf("workflow %s not found", workflow)
+	}
 	for _, r := range w.Rules {
-		below := r.DestW.walk(append(path, Path{w.Name, r}))
-		if below.Cmp(Neg1) == 0 {
-			rv = rv.Add(rv, big.NewInt(r.Size))
-		} else {
-			sz := big.NewInt(r.Size)
-			rv = rv.Add(rv, sz.Mul(sz, below))
+		var match Box
+		match, b = r.Split(b)
+		if !match.Empty() {
+			accepted = h.flow(r.dest, match, accepted)
+		}
+		if b.Empty() {
+			break
 		}
 	}
-	LogPath(w, path, rv)
-	return rv
+	return accepted
 }
 
-func LogPath(w *Workflow, path []Path, n *big.Int) {
-	s := []string{}
-	for _, p := range path {
-		s = append(s, fmt.Sprintf("%s,%s", p.N, p.R))
+// Returns how many distinct parts (of the full 1-4000 range) are accepted.
+func (h *Heap) Combinations() *big.Int {
+	sum := big.NewInt(0)
+	for _, b := range h.AcceptedBoxes() {
+		sum.Add(sum, b.Volume())
 	}
-	glog.Infof("% 5s: %s == %d", w.Name, strings.Join(s, " => "), n)
+	return sum
 }
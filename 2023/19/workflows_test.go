@@ -24,13 +24,13 @@ func Test_Sample(t *testing.T) {
 	for n, p := range testParts {
 		assert.Equal(t, p, *in.Queue[n], "part %d does not match expected", n)
 	}
-	assert.Equal(t, &Rule{attr: "s", op: "<", val: 1351, dest: "px", RemainingSpace: map[string]int64{}}, in.Rules[0])
-	assert.Equal(t, &Rule{dest: "qqz", RemainingSpace: map[string]int64{}}, in.Rules[1])
+	assert.Equal(t, &Rule{attr: "s", op: "<", val: 1351, dest: "px"}, in.Rules[0])
+	assert.Equal(t, &Rule{dest: "qqz"}, in.Rules[1])
 	assert.True(t, in.Rules[1].IsDefault())
 
 	crn := heap.Workflows["crn"]
-	assert.Equal(t, &Rule{attr: "x", op: ">", val: 2662, dest: "A", RemainingSpace: map[string]int64{}}, crn.Rules[0])
-	assert.Equal(t, &Rule{dest: "R", RemainingSpace: map[string]int64{}}, crn.Rules[1])
+	assert.Equal(t, &Rule{attr: "x", op: ">", val: 2662, dest: "A"}, crn.Rules[0])
+	assert.Equal(t, &Rule{dest: "R"}, crn.Rules[1])
 	assert.True(t, crn.Rules[1].IsDefault())
 
 	heap.SortParts()
@@ -46,6 +46,22 @@ func Test_Sample(t *testing.T) {
 	assert.Equal(t, 19114, accepted)
 }
 
+func Test_Graph(t *testing.T) {
+	heap, err := NewHeap("sample")
+	require.NoError(t, err)
+
+	g := heap.Graph()
+	assert.Equal(t, 13, len(g.Nodes))
+	assert.Equal(t, "in", g.Nodes[0].ID)
+	rules := 0
+	for _, w := range heap.Workflows {
+		rules += len(w.Rules)
+	}
+	assert.Equal(t, rules, len(g.Edges))
+	assert.Contains(t, g.String(), `"in" -> "px" [label="s<1351"];`)
+	assert.Contains(t, g.String(), `"in" -> "qqz" [label="else"];`)
+}
+
 func Test_Part1(t *testing.T) {
 	heap, err := NewHeap("input")
 	require.NoError(t, err)
@@ -59,67 +75,63 @@ func Test_Sample_Part2(t *testing.T) {
 	heap, err := NewHeap("sample")
 	require.NoError(t, err)
 
-	heap.BuildGraph()
-	in := heap.Workflows["in"]
-	assert.EqualValues(t, 1350, in.Rules[0].Size)
-	assert.EqualValues(t, 4000, in.Rules[1].RemainingSpace["x"])
-	assert.EqualValues(t, 4000, in.Rules[1].RemainingSpace["m"])
-	assert.EqualValues(t, 4000, in.Rules[1].RemainingSpace["a"])
-	assert.EqualValues(t, 4000-1350, in.Rules[1].RemainingSpace["s"])
-	px := heap.Workflows["px"]
-	assert.EqualValues(t, 2005, px.Rules[0].Size)
-	assert.EqualValues(t, 4000-2090, px.Rules[1].Size)
-	assert.EqualValues(t, 4000, px.Rules[2].RemainingSpace["x"])
-	assert.EqualValues(t, 2090, px.Rules[2].RemainingSpace["m"])
-	assert.EqualValues(t, 4000-2005, px.Rules[2].RemainingSpace["a"])
-	assert.EqualValues(t, 4000, px.Rules[2].RemainingSpace["s"])
 	assert.Equal(t, big.NewInt(167409079868000), heap.Combinations())
 }
 
-func Test_CalcAttrSize(t *testing.T) {
-	rSizes, aSizes := calcAttrSize([]Rule{
-		{attr: "x", op: ">", val: 3000},
-		{attr: "m", op: "<", val: 2},
-		{attr: "a", op: ">", val: 3990},
-		{dest: "default"},
-	}, false)
-	assert.Equal(t, []int64{1000, 1, 10, 0}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 1000, "m": 1, "a": 10, "s": 0}, aSizes)
-
-	rSizes, aSizes = calcAttrSize([]Rule{
-		{attr: "x", op: "<", val: 10},
-		{attr: "x", op: ">", val: 3990},
-	}, false)
-	assert.Equal(t, []int64{9, 10}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 19, "m": 0, "a": 0, "s": 0}, aSizes)
-
-	rSizes, aSizes = calcAttrSize([]Rule{
-		{attr: "x", op: "<", val: 10},
-		{attr: "x", op: ">", val: 3990},
-		{dest: "default", RemainingSpace: map[string]int64{"a": 1000}}, // ignored
-	}, false)
-	assert.Equal(t, []int64{9, 10, 0}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 19, "m": 0, "a": 0, "s": 0}, aSizes)
-
-	rSizes, aSizes = calcAttrSize([]Rule{
-		{attr: "x", op: "<", val: 10},
-		{attr: "x", op: ">", val: 3990},
-		{dest: "default", RemainingSpace: map[string]int64{"a": 1000}}, // used
-	}, true)
-	assert.Equal(t, []int64{9, 10, 0}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 19, "m": 0, "a": 1000, "s": 0}, aSizes)
-
-	rSizes, aSizes = calcAttrSize([]Rule{
-		{attr: "a", op: ">", val: 10},
-		{dest: "default", RemainingSpace: map[string]int64{"a": 1000}}, // used
-	}, true)
-	assert.Equal(t, []int64{3990, 0}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 0, "m": 0, "a": 1000, "s": 0}, aSizes)
-
-	rSizes, aSizes = calcAttrSize([]Rule{
-		{attr: "a", op: ">", val: 3990},
-		{dest: "default", RemainingSpace: map[string]int64{"a": 1000}}, // used
-	}, true)
-	assert.Equal(t, []int64{10, 0}, rSizes)
-	assert.Equal(t, map[string]int64{"x": 0, "m": 0, "a": 10, "s": 0}, aSizes)
+func Test_Part2(t *testing.T) {
+	heap, err := NewHeap("input")
+	require.NoError(t, err)
+
+	combinations := heap.Combinations()
+	t.Logf("Accepted Combinations: %s", combinations)
+}
+
+func Test_Split(t *testing.T) {
+	b := NewBox(1, 4000)
+	match, rest := NewRule("s<1351:px").Split(b)
+	assert.Equal(t, Span{1, 1350}, match.Get("s"))
+	assert.Equal(t, Span{1351, 4000}, rest.Get("s"))
+	assert.Equal(t, Span{1, 4000}, rest.Get("x"))
+
+	match, rest = NewRule("a>3990:A").Split(rest)
+	assert.Equal(t, Span{3991, 4000}, match.Get("a"))
+	assert.Equal(t, Span{1351, 4000}, match.Get("s"))
+	assert.Equal(t, Span{1, 3990}, rest.Get("a"))
+	assert.Equal(t, "{x=1..4000,m=1..4000,a=3991..4000,s=1351..4000}", match.String())
+	assert.EqualValues(t, 4000*4000*10*2650, match.Volume().Int64())
+
+	match, rest = NewRule("m<1:R").Split(rest)
+	assert.True(t, match.Empty())
+	assert.False(t, rest.Empty())
+	assert.EqualValues(t, 0, match.Volume().Int64())
+
+	match, rest = NewRule("R").Split(rest)
+	assert.False(t, match.Empty())
+	assert.True(t, rest.Empty())
+}
+
+func Test_Sample_Boxes(t *testing.T) {
+	heap, err := NewHeap("sample")
+	require.NoError(t, err)
+	parts := append([]*Part{}, heap.Workflows["in"].Queue...)
+	boxes := heap.AcceptedBoxes()
+	heap.SortParts()
+
+	for _, p := range parts {
+		inBoxes := 0
+		for _, b := range boxes {
+			if b.Contains(*p) {
+				inBoxes++
+			}
+		}
+		accepted := false
+		for _, a := range heap.Workflows["A"].Queue {
+			accepted = accepted || a == p
+		}
+		if accepted {
+			assert.Equal(t, 1, inBoxes, "accepted part %s", p)
+		} else {
+			assert.Equal(t, 0, inBoxes, "rejected part %s", p)
+		}
+	}
 }
@@ -6,9 +6,11 @@
 package day3
 
 import (
+	"bufio"
 	"fmt"
 	"log"
 	"math"
+	"os"
 	"strconv"
 )
 
@@ -138,6 +140,21 @@ func (g *Grid) ParseRow(r string) {
 	g.cells = append(g.cells, row)
 }
 
+func NewGrid(filename string) (*Grid, error) {
+	f, err := os.OpenFile(filename, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	grid := Grid{}
+	for s.Scan() {
+		grid.ParseRow(s.Text())
+	}
+	return &grid, nil
+}
+
 func (g *Grid) Print() {
 	for _, cols := range g.cells {
 		for _, cell := range cols {
@@ -179,6 +196,20 @@ func (g *Grid) FindGearRatios() (rv []int) {
 	return
 }
 
+func (g *Grid) PartNumberSum() (sum int) {
+	for _, n := range g.FindPartNumbers() {
+		sum += n
+	}
+	return
+}
+
+func (g *Grid) GearRatioSum() (sum int) {
+	for _, n := range g.FindGearRatios() {
+		sum += n
+	}
+	return
+}
+
 // returns a cell from the grid (or nil, if pos is invalid)
 func (g *Grid) Cell(p Pos) *Cell {
 	if p.row < 0 || p.row > len(g.cells)-1 || p.col < 0 || p.col > len(g.cells[0])-1 {
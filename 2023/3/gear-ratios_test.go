@@ -41,40 +41,32 @@ func run(t *testing.T, filename string, skipLines int, limitLines int) *Grid {
 	return &grid
 }
 
-func part1(grid *Grid) int {
-	sum := 0
-	for _, n := range grid.FindPartNumbers() {
-		sum += n
-	}
-	return sum
+func Test_Sample(t *testing.T) {
+	grid := run(t, "sample", -1, -1)
+	assert.Equal(t, 4361, grid.PartNumberSum())
+	assert.Equal(t, 467835, grid.GearRatioSum())
 }
 
-func part2(grid *Grid) int {
-	sum := 0
-	for _, n := range grid.FindGearRatios() {
-		sum += n
+func Test_NewGrid(t *testing.T) {
+	grid, err := NewGrid("sample")
+	if err != nil {
+		t.Fatal(err)
 	}
-	return sum
-}
-
-func Test_Sample(t *testing.T) {
-	grid := run(t, "sample", -1, -1)
-	assert.Equal(t, 4361, part1(grid))
-	assert.Equal(t, 467835, part2(grid))
+	assert.Equal(t, 4361, grid.PartNumberSum())
 }
 
 func Test_Input(t *testing.T) {
 	grid := run(t, "input", -1, -1)
-	log.Printf("Sum is: %d", part1(grid))
-	log.Printf("Gear Ratio Sum is: %d", part2(grid))
+	log.Printf("Sum is: %d", grid.PartNumberSum())
+	log.Printf("Gear Ratio Sum is: %d", grid.GearRatioSum())
 }
 
 func Test__Head(t *testing.T) {
 	grid := run(t, "input", -1, 4)
-	log.Printf("Sum is: %d", part1(grid))
+	log.Printf("Sum is: %d", grid.PartNumberSum())
 }
 
 func Test_Middle(t *testing.T) {
 	grid := run(t, "input", 136, 140)
-	log.Printf("Sum is: %d", part1(grid))
+	log.Printf("Sum is: %d", grid.PartNumberSum())
 }
@@ -0,0 +1,20 @@
+package day3
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 3, 1, solver.Func(func(filename string) (any, error) {
+		grid, err := NewGrid(filename)
+		if err != nil {
+			return nil, err
+		}
+		return grid.PartNumberSum(), nil
+	}))
+	solver.Register(2023, 3, 2, solver.Func(func(filename string) (any, error) {
+		grid, err := NewGrid(filename)
+		if err != nil {
+			return nil, err
+		}
+		return grid.GearRatioSum(), nil
+	}))
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/mattbnz/aoc/lib/interval"
 )
 
 type cacheKey struct {
@@ -91,6 +92,16 @@ func (m *Mapping) ToSource(id int, max int) (int, Override) {
 	return id, Override{SourceBase: lastEnd, DestBase: lastEnd, Count: max - lastEnd}
 }
 
+// Returns the overrides as a piecewise shift of ids (anything not overridden
+// maps to itself).
+func (m *Mapping) Piecewise() (interval.Mapping, error) {
+	pieces := []interval.Piece{}
+	for _, o := range m.Overrides {
+		pieces = append(pieces, interval.Piece{From: interval.FromCount(o.SourceBase, o.Count), Delta: o.DestBase - o.SourceBase})
+	}
+	return interval.NewMapping(pieces...)
+}
+
 func (m *Mapping) FromCache(dest string, id int) (rv int, bounds Override, found bool) {
 	for k, o := range m.Cache {
 		if k.dest != dest {
@@ -267,6 +278,47 @@ func (a *Almanac) BoundedLookup(source string, id int, dest string) (int, Overri
 	return d2, final
 }
 
+// Returns the seeds line read as pairs of (start, count).
+func (a *Almanac) SeedRanges() interval.Set {
+	ranges := []interval.Interval{}
+	for n := 0; n+1 < len(a.Seeds); n += 2 {
+		ranges = append(ranges, interval.FromCount(a.Seeds[n], a.Seeds[n+1]))
+	}
+	return interval.NewSet(ranges...)
+}
+
+// Composes every map on the way from source to dest into a single mapping.
+func (a *Almanac) Chain(source, dest string) (rv interval.Mapping, err error) {
+	for source != dest {
+		m := a.getMap(source)
+		if m == nil {
+			return rv, fmt.Errorf("no map from %s towards %s", source, dest)
+		}
+		pm, err := m.Piecewise()
+		if err != nil {
+			return rv, fmt.Errorf("%s: %w", m, err)
+		}
+		rv = rv.Then(pm)
+		source = m.Dest
+	}
+	return
+}
+
+// Pushes every seed range through all the maps at once, returning the
+// lowest location any of them reach.
+func (a *Almanac) BestLocationRanges() (int, error) {
+	chain, err := a.Chain("seed", "location")
+	if err != nil {
+		return -1, err
+	}
+	locs := chain.Apply(a.SeedRanges())
+	if locs.Empty() {
+		return -1, fmt.Errorf("no seeds")
+	}
+	glog.V(1).Infof("%d seeds reach %d location ranges", a.SeedRanges().Len(), len(locs.Intervals()))
+	return locs.Min(), nil
+}
+
 func (a *Almanac) BestLocation() int {
 	locs := []int{}
 	for _, s := range a.Seeds {
@@ -4,6 +4,7 @@ import (
 	"log"
 	"testing"
 
+	"github.com/mattbnz/aoc/lib/answers/answerstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -150,6 +151,10 @@ func Test_Sample(t *testing.T) {
 	assert.Equal(t, 35, almanac.BestLocation())
 	assert.Equal(t, 46, almanac.BestLocation2())
 	assert.Equal(t, 46, almanac.BestLocation2b())
+
+	best, err := almanac.BestLocationRanges()
+	require.NoError(t, err)
+	assert.Equal(t, 46, best)
 	for seed, soil := range map[int]int{
 		0:  0,
 		1:  1,
@@ -183,6 +188,25 @@ func Test_Sample(t *testing.T) {
 	}
 }
 
+func Test_Chain(t *testing.T) {
+	almanac, err := NewAlmanac("sample")
+	require.NoError(t, err)
+	assert.Equal(t, 27, almanac.SeedRanges().Len())
+
+	chain, err := almanac.Chain("seed", "location")
+	require.NoError(t, err)
+	for _, seed := range []int{0, 13, 14, 55, 79, 82, 99, 100} {
+		assert.Equal(t, almanac.Lookup("seed", seed, "location"), chain.Map(seed), "seed %d", seed)
+	}
+
+	soil, err := almanac.Chain("seed", "soil")
+	require.NoError(t, err)
+	assert.Equal(t, "{[57,70) [81,95)}", soil.Apply(almanac.SeedRanges()).String())
+
+	_, err = almanac.Chain("location", "seed")
+	assert.Error(t, err)
+}
+
 func Test_Part1(t *testing.T) {
 	almanac, err := NewAlmanac("input")
 	require.NoError(t, err)
@@ -194,20 +218,22 @@ func Test_Part2(t *testing.T) {
 	require.NoError(t, err)
 
 	best := almanac.BestLocation2()
-	assert.Less(t, best, 53266420, "guess 1")
+	answerstest.AssertWrong(t, 2, best)
 	log.Printf("Best Location for all seeds is: %d (WRONG)", best)
 
 	best = almanac.BestLocation2b()
-	assert.Less(t, best, 53266420, "guess 1")
-	assert.Greater(t, best, 0, "guess 2")
+	answerstest.AssertWrong(t, 2, best)
+	log.Printf("Best Location for all seeds is: %d (WRONG)", best)
 
-	log.Printf("Best Location for all seeds is: %d", best)
+	best, err = almanac.BestLocationRanges()
+	require.NoError(t, err)
+	answerstest.Assert(t, 2, best)
+	log.Printf("Best Location for all seed ranges is: %d", best)
 }
 
 func Test_Test(t *testing.T) {
 	almanac, err := NewAlmanac("input")
 	require.NoError(t, err)
 	best := almanac.BestLocation2b()
-	assert.Less(t, best, 53266420, "guess 1")
-	assert.Greater(t, best, 0, "guess 2")
+	answerstest.AssertWrong(t, 2, best)
 }
@@ -0,0 +1,20 @@
+package day5
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 5, 1, solver.Func(func(filename string) (any, error) {
+		almanac, err := NewAlmanac(filename)
+		if err != nil {
+			return nil, err
+		}
+		return almanac.BestLocation(), nil
+	}))
+	solver.Register(2023, 5, 2, solver.Func(func(filename string) (any, error) {
+		almanac, err := NewAlmanac(filename)
+		if err != nil {
+			return nil, err
+		}
+		return almanac.BestLocationRanges()
+	}))
+}
@@ -43,9 +43,10 @@ func Test_Hand(t *testing.T) {
 }
 
 func Test_JokerHands(t *testing.T) {
-	h1 := MustJokerHand("33332")
-	h2 := MustJokerHand("3333J")
-	assert.Equal(t, -1, HandSortFunc(h2, h1), "joker not less than 2!")
+	// Both are four of a kind, so the joker being weakest decides it.
+	h1 := MustJokerHand("QQQQ2")
+	h2 := MustJokerHand("JKKK2")
+	assert.Equal(t, -1, HandSortFunc(h2, h1), "joker not less than Q!")
 }
 
 func Test_HandCmp(t *testing.T) {
@@ -106,7 +107,7 @@ func Test_Sample(t *testing.T) {
 
 	jokerHands, err := NewHands("sample", true)
 	require.NoError(t, err)
-	assert.Equal(t, 5095, jokerHands.Winnings())
+	assert.Equal(t, 5905, jokerHands.Winnings())
 
 }
 
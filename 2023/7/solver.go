@@ -0,0 +1,20 @@
+package day7
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 7, 1, solver.Func(func(filename string) (any, error) {
+		hands, err := NewHands(filename, false)
+		if err != nil {
+			return nil, err
+		}
+		return hands.Winnings(), nil
+	}))
+	solver.Register(2023, 7, 2, solver.Func(func(filename string) (any, error) {
+		hands, err := NewHands(filename, true)
+		if err != nil {
+			return nil, err
+		}
+		return hands.Winnings(), nil
+	}))
+}
@@ -7,12 +7,17 @@ package day8
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/mattbnz/aoc/lib/graph"
+	"github.com/mattbnz/aoc/lib/numtheory"
 )
 
 type Node struct {
@@ -68,6 +73,30 @@ func NewMap(filename string) (m Map, err error) {
 	return
 }
 
+// Returns the map as a graph, with each node's L and R elements as labelled
+// edges (a single "LR" edge when both go to the same place).
+func (m Map) Graph() *graph.Graph {
+	names := []string{}
+	for name := range m.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	g := graph.New("map")
+	for _, name := range names {
+		g.AddNode(name, "")
+	}
+	for _, name := range names {
+		n := m.Nodes[name]
+		if n.Elements['L'] == n.Elements['R'] {
+			g.AddEdge(name, n.Elements['L'], "LR")
+			continue
+		}
+		g.AddEdge(name, n.Elements['L'], "L")
+		g.AddEdge(name, n.Elements['R'], "R")
+	}
+	return g
+}
+
 func ZZZ(n string) bool {
 	return n == "ZZZ"
 }
@@ -129,15 +158,135 @@ func (m Map) SimultaneousSteps() int {
 	return step
 }
 
-func (m Map) LCMSteps() int {
-	aSteps := []int{}
+// Orbit is the path a ghost takes through (instruction, node) states,
+// which must eventually loop. After step s the ghost is at an end if s is
+// in PreZ, or if s >= Prefix and s is in CycleZ modulo Len.
+type Orbit struct {
+	Start  string
+	Prefix int   // steps before the loop is entered
+	Len    int   // steps around the loop
+	PreZ   []int // steps < Prefix that arrive at an end
+	CycleZ []int // steps in [Prefix, Prefix+Len) that arrive at an end
+}
+
+type state struct {
+	ip   int
+	node string
+}
+
+// Follows the ghost from start until its state repeats.
+func (m Map) Orbit(start string, atEnd func(string) bool) Orbit {
+	o := Orbit{Start: start}
+	seen := map[state]int{}
+	zs := []int{}
+	node := m.Nodes[start]
+	for step := 0; ; step++ {
+		st := state{step % len(m.Instructions), node.Name}
+		if first, found := seen[st]; found {
+			o.Prefix, o.Len = first, step-first
+			break
+		}
+		seen[st] = step
+		if atEnd(node.Name) {
+			zs = append(zs, step)
+		}
+		node = m.Nodes[node.Elements[rune(m.Instructions[st.ip])]]
+	}
+	for _, z := range zs {
+		if z < o.Prefix {
+			o.PreZ = append(o.PreZ, z)
+		} else {
+			o.CycleZ = append(o.CycleZ, z)
+		}
+	}
+	return o
+}
+
+// Whether the ghost is at an end after step s.
+func (o Orbit) At(s int) bool {
+	if s < o.Prefix {
+		return slices.Contains(o.PreZ, s)
+	}
+	return slices.Contains(o.CycleZ, o.Prefix+(s-o.Prefix)%o.Len)
+}
+
+// The orbit of every ghost, one per node ending in A.
+func (m Map) Orbits() []Orbit {
+	starts := []string{}
 	for name := range m.Nodes {
 		if strings.HasSuffix(name, "A") {
-			steps := m.StepsFrom(name, EndsZ)
-			glog.Infof("%s takes %d steps to find Z", name, steps)
-			aSteps = append(aSteps, steps)
+			starts = append(starts, name)
+		}
+	}
+	sort.Strings(starts)
+	orbits := []Orbit{}
+	for _, start := range starts {
+		o := m.Orbit(start, EndsZ)
+		glog.Infof("%s loops every %d steps after %d, ends at %v then %v", start, o.Len, o.Prefix, o.PreZ, o.CycleZ)
+		orbits = append(orbits, o)
+	}
+	return orbits
+}
+
+// Returns the first step (after at least one) at which every ghost is at an
+// end, or numtheory.ErrNoSolution if that never happens.
+func Simultaneous(orbits []Orbit) (int, error) {
+	prefix := 0
+	for _, o := range orbits {
+		prefix = max(prefix, o.Prefix)
+	}
+	// Before every ghost is in its loop, just look.
+	for s := 1; s < prefix; s++ {
+		all := true
+		for _, o := range orbits {
+			if !o.At(s) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return s, nil
 		}
 	}
-	glog.Infof("%#v", aSteps)
-	return LCM(aSteps[0], aSteps[1], aSteps[2:]...)
+
+	// After, each ghost needs s to be one of its end offsets modulo its
+	// loop length. Try every combination of offsets.
+	cs := []numtheory.Congruence{{R: 0, M: 1}}
+	for _, o := range orbits {
+		next := []numtheory.Congruence{}
+		for _, c := range cs {
+			for _, z := range o.CycleZ {
+				n, err := numtheory.CRT(c, numtheory.Congruence{R: z, M: o.Len})
+				if errors.Is(err, numtheory.ErrNoSolution) {
+					continue
+				} else if err != nil {
+					return 0, err
+				}
+				if !slices.Contains(next, n) {
+					next = append(next, n)
+				}
+			}
+		}
+		cs = next
+	}
+	best := -1
+	for _, c := range cs {
+		s := c.R
+		if low := max(prefix, 1); s < low {
+			s += (low - s + c.M - 1) / c.M * c.M
+		}
+		if best == -1 || s < best {
+			best = s
+		}
+	}
+	if best == -1 {
+		return 0, fmt.Errorf("ghosts are never all at an end: %w", numtheory.ErrNoSolution)
+	}
+	return best, nil
+}
+
+// Returns how many steps until every ghost is at an end at once, from the
+// orbits they follow.
+func (m Map) CycleSteps() (int, error) {
+	return Simultaneous(m.Orbits())
 }
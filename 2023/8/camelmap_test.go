@@ -1,9 +1,14 @@
 package day8
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"math/rand"
+	"strings"
 	"testing"
 
+	"github.com/mattbnz/aoc/lib/numtheory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +23,18 @@ func Test_Parsing(t *testing.T) {
 	assert.Equal(t, "CCC", m.Nodes["AAA"].Elements['R'])
 }
 
+func Test_Graph(t *testing.T) {
+	m, err := NewMap("sample")
+	require.NoError(t, err)
+
+	g := m.Graph()
+	assert.Equal(t, 7, len(g.Nodes))
+	assert.Equal(t, "AAA", g.Nodes[0].ID)
+	assert.Equal(t, 2+2+2+1+1+1+1, len(g.Edges))
+	assert.Contains(t, g.String(), `"CCC" -> "ZZZ" [label="L"];`)
+	assert.Contains(t, g.String(), `"DDD" -> "DDD" [label="LR"];`)
+}
+
 func Test_Sample(t *testing.T) {
 	m, err := NewMap("sample")
 	require.NoError(t, err)
@@ -38,10 +55,113 @@ func Test_Part2_Sample(t *testing.T) {
 	m, err := NewMap("sample3")
 	require.NoError(t, err)
 	assert.Equal(t, 6, m.SimultaneousSteps())
+
+	orbits := m.Orbits()
+	assert.Equal(t, []Orbit{
+		{Start: "11A", Prefix: 1, Len: 2, CycleZ: []int{2}},
+		{Start: "22A", Prefix: 1, Len: 6, CycleZ: []int{3, 6}},
+	}, orbits)
+	steps, err := m.CycleSteps()
+	require.NoError(t, err)
+	assert.Equal(t, 6, steps)
+}
+
+// Builds a map from "AAA=BBB,CCC" style lines.
+func makeMap(instructions string, lines ...string) Map {
+	m := Map{Instructions: instructions, Nodes: map[string]*Node{}}
+	for _, l := range lines {
+		name, elements, _ := strings.Cut(l, "=")
+		left, right, _ := strings.Cut(elements, ",")
+		m.Nodes[name] = &Node{Name: name, Elements: map[rune]string{'L': left, 'R': right}}
+	}
+	return m
+}
+
+// Ghosts whose first Z isn't their loop length, that pass through Z before
+// the loop, or that have several Zs in it, where LCM gives the wrong answer.
+func Test_Unlucky(t *testing.T) {
+	for name, m := range map[string]Map{
+		"offset": makeMap("L",
+			"11A=11B,11B", "11B=11Z,11Z", "11Z=11C,11C", "11C=11B,11B",
+			"22A=22Z,22Z", "22Z=22B,22B", "22B=22Z,22Z"),
+		"prefix": makeMap("L",
+			"11A=11Z,11Z", "11Z=11B,11B", "11B=11C,11C", "11C=11B,11B",
+			"22A=22Z,22Z", "22Z=22B,22B", "22B=22Z,22Z"),
+		"twoZ": makeMap("LR",
+			"11A=11B,11B", "11B=11Z,11Y", "11Z=11B,11B", "11Y=11Z,11Z",
+			"22A=22B,22B", "22B=22C,22C", "22C=22D,22D", "22D=22E,22E", "22E=22Z,22Z", "22Z=22A,22A"),
+		"shared": makeMap("L",
+			"11A=11B,11B", "11B=11C,11C", "11C=11D,11D", "11D=11Z,11Z", "11Z=11A,11A",
+			"22A=22B,22B", "22B=22C,22C", "22C=22D,22D", "22D=22E,22E", "22E=22F,22F", "22F=22G,22G",
+			"22G=22H,22H", "22H=22I,22I", "22I=22Z,22Z", "22Z=22A,22A"),
+	} {
+		steps, err := m.CycleSteps()
+		require.NoError(t, err, name)
+		assert.Equal(t, m.SimultaneousSteps(), steps, name)
+	}
+
+	// 11A only ends on odd steps, 22A only on even ones.
+	m := makeMap("L", "11A=11Z,11Z", "11Z=11A,11A", "22A=22B,22B", "22B=22Z,22Z", "22Z=22B,22B")
+	_, err := m.CycleSteps()
+	assert.True(t, errors.Is(err, numtheory.ErrNoSolution), err)
+}
+
+// Random maps agree with walking every ghost, when they ever all finish.
+func Test_CycleStepsBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	suffix := []string{"A", "Z", "X", "Z"}
+	for n := 0; n < 300; n++ {
+		names := []string{}
+		for i := 0; i < r.Intn(10)+2; i++ {
+			names = append(names, fmt.Sprintf("%02d%s", i, suffix[i%len(suffix)]))
+		}
+		lines := []string{}
+		for _, name := range names {
+			lines = append(lines, name+"="+names[r.Intn(len(names))]+","+names[r.Intn(len(names))])
+		}
+		instructions := ""
+		for i := 0; i < r.Intn(4)+1; i++ {
+			instructions += string("LR"[r.Intn(2)])
+		}
+		m := makeMap(instructions, lines...)
+
+		steps, err := m.CycleSteps()
+		want := bruteSteps(m, 10000)
+		if want == -1 {
+			assert.True(t, errors.Is(err, numtheory.ErrNoSolution), "%v: %v %v", lines, steps, err)
+			continue
+		}
+		require.NoError(t, err, lines)
+		assert.Equal(t, want, steps, "%s %v", instructions, lines)
+		assert.Equal(t, want, m.SimultaneousSteps(), lines)
+	}
+}
+
+// Walks every ghost for up to limit steps, -1 if they never all finish.
+func bruteSteps(m Map, limit int) int {
+	nodes := []string{}
+	for name := range m.Nodes {
+		if strings.HasSuffix(name, "A") {
+			nodes = append(nodes, name)
+		}
+	}
+	for step := 1; step <= limit; step++ {
+		all := true
+		for i, n := range nodes {
+			nodes[i] = m.Nodes[n].Elements[rune(m.Instructions[(step-1)%len(m.Instructions)])]
+			all = all && EndsZ(nodes[i])
+		}
+		if all {
+			return step
+		}
+	}
+	return -1
 }
 
 func Test_Part2(t *testing.T) {
 	m, err := NewMap("input")
 	require.NoError(t, err)
-	log.Printf("LCM Steps: %d", m.LCMSteps())
+	steps, err := m.CycleSteps()
+	require.NoError(t, err)
+	log.Printf("Cycle Steps: %d", steps)
 }
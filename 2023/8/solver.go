@@ -0,0 +1,20 @@
+package day8
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 8, 1, solver.Func(func(filename string) (any, error) {
+		m, err := NewMap(filename)
+		if err != nil {
+			return nil, err
+		}
+		return m.StepsFrom("AAA", ZZZ), nil
+	}))
+	solver.Register(2023, 8, 2, solver.Func(func(filename string) (any, error) {
+		m, err := NewMap(filename)
+		if err != nil {
+			return nil, err
+		}
+		return m.CycleSteps()
+	}))
+}
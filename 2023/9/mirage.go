@@ -1,12 +1,14 @@
 // Copyright (C) 2023 Matt Brown
 
-// Advent of Code 2023 - Day 8.
-// Haunted Wasteland.
+// Advent of Code 2023 - Day 9.
+// Mirage Maintenance.
 
 package day9
 
 import (
 	"bufio"
+	"fmt"
+	"math/big"
 	"os"
 )
 
@@ -30,46 +32,41 @@ func NewScan(filename string) (scan Scan, err error) {
 	return
 }
 
-func (s *Scan) ExtrapolateAndSum() int {
-	sum := 0
-	for _, seq := range s.Readings {
-		sum += s.Extrapolate(seq)
+// Returns the sum of f applied to each reading's polynomial.
+func (s *Scan) sum(f func(r Reading, p *Polynomial) *big.Int) (*big.Int, error) {
+	sum := new(big.Int)
+	for n, r := range s.Readings {
+		p, err := r.Polynomial()
+		if err != nil {
+			return nil, fmt.Errorf("reading %d: %w", n+1, err)
+		}
+		sum.Add(sum, f(r, p))
 	}
-	return sum
+	return sum, nil
 }
 
-func (s *Scan) Extrapolate(r Reading) int {
-	diffs := Reading{}
-	last := r[0]
-	for n := 1; n < len(r); n++ {
-		diffs = append(diffs, r[n]-last)
-		last = r[n]
-	}
-	if Sum(diffs) == 0 {
-		return r[len(r)-1]
-	}
-	nextDiff := s.Extrapolate(diffs)
-	return r[len(r)-1] + nextDiff
+func (s *Scan) ExtrapolateAndSum() (*big.Int, error) {
+	return s.sum(func(r Reading, p *Polynomial) *big.Int { return p.At(len(r)) })
 }
 
-func (s *Scan) ExtrapolateHistoryAndSum() int {
-	sum := 0
-	for _, seq := range s.Readings {
-		sum += s.ExtrapolateHistory(seq)
+// Returns the value following the reading.
+func (s *Scan) Extrapolate(r Reading) (*big.Int, error) {
+	p, err := r.Polynomial()
+	if err != nil {
+		return nil, err
 	}
-	return sum
+	return p.At(len(r)), nil
 }
 
-func (s *Scan) ExtrapolateHistory(r Reading) int {
-	diffs := Reading{}
-	last := r[0]
-	for n := 1; n < len(r); n++ {
-		diffs = append(diffs, r[n]-last)
-		last = r[n]
-	}
-	if Sum(diffs) == 0 {
-		return r[0]
+func (s *Scan) ExtrapolateHistoryAndSum() (*big.Int, error) {
+	return s.sum(func(r Reading, p *Polynomial) *big.Int { return p.At(-1) })
+}
+
+// Returns the value preceding the reading.
+func (s *Scan) ExtrapolateHistory(r Reading) (*big.Int, error) {
+	p, err := r.Polynomial()
+	if err != nil {
+		return nil, err
 	}
-	nextDiff := s.ExtrapolateHistory(diffs)
-	return r[0] - nextDiff
+	return p.At(-1), nil
 }
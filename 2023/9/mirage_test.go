@@ -1,47 +1,139 @@
 package day9
 
 import (
+	"errors"
 	"log"
+	"math"
+	"math/big"
 	"testing"
 
+	"github.com/mattbnz/aoc/lib/answers/answerstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func extrapolate(t *testing.T, r Reading) int64 {
+	v, err := (&Scan{}).Extrapolate(r)
+	require.NoError(t, err, r)
+	return v.Int64()
+}
+
+func history(t *testing.T, r Reading) int64 {
+	v, err := (&Scan{}).ExtrapolateHistory(r)
+	require.NoError(t, err, r)
+	return v.Int64()
+}
+
 func Test_Extrapolate(t *testing.T) {
-	s := Scan{}
-	assert.Equal(t, 0, s.Extrapolate(Reading{0}))
-	assert.Equal(t, 0, s.Extrapolate(Reading{0, 0, 0}))
-	assert.Equal(t, 1, s.Extrapolate(Reading{1, 1, 1}))
+	assert.Equal(t, int64(0), extrapolate(t, Reading{0}))
+	assert.Equal(t, int64(0), extrapolate(t, Reading{0, 0, 0}))
+	assert.Equal(t, int64(1), extrapolate(t, Reading{1, 1, 1}))
+	assert.Equal(t, int64(68), extrapolate(t, Reading{10, 13, 16, 21, 30, 45}))
 }
 
 func Test_ExtrapolateHistory(t *testing.T) {
-	s := Scan{}
-	assert.Equal(t, 0, s.ExtrapolateHistory(Reading{0}))
-	assert.Equal(t, 0, s.ExtrapolateHistory(Reading{0, 0, 0}))
-	assert.Equal(t, 1, s.ExtrapolateHistory(Reading{1, 1, 1}))
-	assert.Equal(t, -2, s.ExtrapolateHistory(Reading{0, 2, 4, 6}))
-	assert.Equal(t, 5, s.ExtrapolateHistory(Reading{3, 3, 5, 9, 15}))
-	assert.Equal(t, 5, s.ExtrapolateHistory(Reading{10, 13, 16, 21, 30, 45}))
+	assert.Equal(t, int64(0), history(t, Reading{0}))
+	assert.Equal(t, int64(0), history(t, Reading{0, 0, 0}))
+	assert.Equal(t, int64(1), history(t, Reading{1, 1, 1}))
+	assert.Equal(t, int64(-2), history(t, Reading{0, 2, 4, 6}))
+	assert.Equal(t, int64(5), history(t, Reading{3, 3, 5, 9, 15}))
+	assert.Equal(t, int64(5), history(t, Reading{10, 13, 16, 21, 30, 45}))
+}
+
+func Test_Polynomial(t *testing.T) {
+	p, err := Reading{1, 3, 6, 10, 15, 21}.Polynomial()
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Degree())
+	assert.Equal(t, []*big.Rat{big.NewRat(1, 1), big.NewRat(3, 2), big.NewRat(1, 2)}, p.Coefficients())
+	assert.Equal(t, "1/2x^2 + 3/2x + 1", p.String())
+	assert.Equal(t, "28", p.At(6).String())
+	assert.Equal(t, "0", p.At(-1).String())
+	assert.Equal(t, "0", p.At(-2).String())
+	assert.Equal(t, "1", p.At(-3).String())
+	// Far beyond what an int could hold.
+	assert.Equal(t, "42535295865117307937533511947398414336", p.At(math.MaxInt64).String())
+
+	p, err = Reading{0, 0, 0}.Polynomial()
+	require.NoError(t, err)
+	assert.Equal(t, -1, p.Degree())
+	assert.Equal(t, "0", p.String())
+}
+
+// The polynomial goes through every value, both in difference and in
+// coefficient form.
+func Test_Coefficients(t *testing.T) {
+	for want, r := range map[string]Reading{
+		"7":                      {7, 7, 7},
+		"-2x + 3":                {3, 1, -1},
+		"x^3 - x":                {0, 0, 6, 24, 60},
+		"-1/2x^2 + 1/2x - 4":     {-4, -4, -5, -7, -10},
+		"1/6x^3 + 1/2x^2 + 1/3x": {0, 1, 4, 10, 20, 35},
+	} {
+		p, err := r.Polynomial()
+		require.NoError(t, err, want)
+		assert.Equal(t, want, p.String())
+		for i, v := range r {
+			assert.Equal(t, int64(v), p.At(i).Int64(), "%s at %d", want, i)
+			sum, pow := new(big.Rat), big.NewRat(1, 1)
+			for _, c := range p.Coefficients() {
+				sum.Add(sum, new(big.Rat).Mul(c, pow))
+				pow.Mul(pow, big.NewRat(int64(i), 1))
+			}
+			assert.Equal(t, big.NewRat(int64(v), 1).String(), sum.String(), "%s at %d", want, i)
+		}
+	}
+}
+
+func Test_NoPattern(t *testing.T) {
+	for _, r := range []Reading{{}, {5}, {1, 2}, {0, 0, 1}, {1, 2, 4, 8, 16}} {
+		_, err := r.Polynomial()
+		assert.True(t, errors.Is(err, ErrNoPattern), "%v: %v", r, err)
+	}
+	scan := Scan{Readings: []Reading{{1, 1}, {1, 2, 4}}}
+	_, err := scan.ExtrapolateAndSum()
+	assert.ErrorContains(t, err, "reading 2")
+}
+
+// Long readings overflow int differences, but stay exact.
+func Test_Overflow(t *testing.T) {
+	r := Reading{}
+	for i := 0; i < 30; i++ {
+		r = append(r, int(new(big.Int).Exp(big.NewInt(int64(i)), big.NewInt(12), nil).Int64()))
+	}
+	p, err := r.Polynomial()
+	require.NoError(t, err)
+	assert.Equal(t, 12, p.Degree())
+	assert.Equal(t, new(big.Int).Exp(big.NewInt(1000), big.NewInt(12), nil), p.At(1000))
+	assert.Equal(t, new(big.Int).Exp(big.NewInt(-5), big.NewInt(12), nil), p.At(-5))
 }
 
 func Test_Sample(t *testing.T) {
 	scan, err := NewScan("sample")
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(scan.Readings))
-	assert.Equal(t, 114, scan.ExtrapolateAndSum())
+	sum, err := scan.ExtrapolateAndSum()
+	require.NoError(t, err)
+	assert.Equal(t, "114", sum.String())
 
-	assert.Equal(t, 2, scan.ExtrapolateHistoryAndSum())
+	sum, err = scan.ExtrapolateHistoryAndSum()
+	require.NoError(t, err)
+	assert.Equal(t, "2", sum.String())
 }
 
 func Test_Part1(t *testing.T) {
 	scan, err := NewScan("input")
 	require.NoError(t, err)
-	log.Printf("Sum is: %d", scan.ExtrapolateAndSum())
+	sum, err := scan.ExtrapolateAndSum()
+	require.NoError(t, err)
+	answerstest.Assert(t, 1, sum)
+	log.Printf("Sum is: %s", sum)
 }
 
 func Test_Part2(t *testing.T) {
 	scan, err := NewScan("input")
 	require.NoError(t, err)
-	log.Printf("History Sum is: %d", scan.ExtrapolateHistoryAndSum())
+	sum, err := scan.ExtrapolateHistoryAndSum()
+	require.NoError(t, err)
+	answerstest.Assert(t, 2, sum)
+	log.Printf("History Sum is: %s", sum)
 }
@@ -0,0 +1,138 @@
+package day9
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrNoPattern = errors.New("differences never reach zero")
+
+// Polynomial is the lowest degree polynomial through a reading, taking
+// index i to the ith value. It is kept in Newton's forward difference form,
+// p(x) = sum over k of diffs[k] * (x choose k), which stays in integers.
+type Polynomial struct {
+	diffs []*big.Int
+}
+
+func isZero(l []*big.Int) bool {
+	for _, v := range l {
+		if v.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Finds the polynomial by differencing the reading until a row is all
+// zero, or returns ErrNoPattern if the rows run out first (in which case
+// the reading only fits a polynomial of higher degree than it can show).
+func (r Reading) Polynomial() (*Polynomial, error) {
+	row := []*big.Int{}
+	for _, v := range r {
+		row = append(row, big.NewInt(int64(v)))
+	}
+	p := &Polynomial{}
+	for len(row) > 0 {
+		if isZero(row) {
+			return p, nil
+		}
+		p.diffs = append(p.diffs, row[0])
+		next := []*big.Int{}
+		for n := 1; n < len(row); n++ {
+			next = append(next, new(big.Int).Sub(row[n], row[n-1]))
+		}
+		row = next
+	}
+	return nil, fmt.Errorf("%w in %d values", ErrNoPattern, len(r))
+}
+
+// The degree of the polynomial, -1 if it is zero everywhere.
+func (p *Polynomial) Degree() int {
+	return len(p.diffs) - 1
+}
+
+// The value at index x, which can be anywhere, before or after the
+// reading.
+func (p *Polynomial) At(x int) *big.Int {
+	sum := new(big.Int)
+	choose := big.NewInt(1) // x choose k
+	bx := big.NewInt(int64(x))
+	for k, d := range p.diffs {
+		if k > 0 {
+			// (x choose k) = (x choose k-1) * (x-k+1) / k, always exact.
+			f := new(big.Int).Sub(bx, big.NewInt(int64(k-1)))
+			choose.Mul(choose, f).Quo(choose, big.NewInt(int64(k)))
+		}
+		sum.Add(sum, new(big.Int).Mul(d, choose))
+	}
+	return sum
+}
+
+// Returns the coefficients of p(x) = c[0] + c[1]x + c[2]x^2..., one per
+// degree.
+func (p *Polynomial) Coefficients() []*big.Rat {
+	coeffs := make([]*big.Rat, len(p.diffs))
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+	}
+	// falling is x(x-1)...(x-k+1) as coefficients, divided by k! to give
+	// (x choose k).
+	falling := []*big.Rat{big.NewRat(1, 1)}
+	fact := big.NewInt(1)
+	for k, d := range p.diffs {
+		if k > 0 {
+			fact.Mul(fact, big.NewInt(int64(k)))
+			next := make([]*big.Rat, k+1)
+			for i := range next {
+				next[i] = new(big.Rat)
+			}
+			shift := big.NewRat(int64(-(k - 1)), 1)
+			for i, c := range falling {
+				next[i+1].Add(next[i+1], c)
+				next[i].Add(next[i], new(big.Rat).Mul(c, shift))
+			}
+			falling = next
+		}
+		scale := new(big.Rat).SetFrac(d, fact)
+		for i, c := range falling {
+			coeffs[i].Add(coeffs[i], new(big.Rat).Mul(c, scale))
+		}
+	}
+	return coeffs
+}
+
+// Returns the polynomial in x, highest power first, like "1/2x^2 + 3/2x + 1".
+func (p *Polynomial) String() string {
+	coeffs := p.Coefficients()
+	var sb strings.Builder
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		c := coeffs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		abs := new(big.Rat).Abs(c)
+		switch {
+		case sb.Len() == 0 && c.Sign() < 0:
+			sb.WriteString("-")
+		case sb.Len() > 0 && c.Sign() < 0:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		if i == 0 || abs.Cmp(big.NewRat(1, 1)) != 0 {
+			sb.WriteString(abs.RatString())
+		}
+		if i > 0 {
+			sb.WriteString("x")
+		}
+		if i > 1 {
+			fmt.Fprintf(&sb, "^%d", i)
+		}
+	}
+	if sb.Len() == 0 {
+		return "0"
+	}
+	return sb.String()
+}
@@ -0,0 +1,20 @@
+package day9
+
+import "github.com/mattbnz/aoc/lib/solver"
+
+func init() {
+	solver.Register(2023, 9, 1, solver.Func(func(filename string) (any, error) {
+		scan, err := NewScan(filename)
+		if err != nil {
+			return nil, err
+		}
+		return scan.ExtrapolateAndSum()
+	}))
+	solver.Register(2023, 9, 2, solver.Func(func(filename string) (any, error) {
+		scan, err := NewScan(filename)
+		if err != nil {
+			return nil, err
+		}
+		return scan.ExtrapolateHistoryAndSum()
+	}))
+}
@@ -20,10 +20,3 @@ func numberList(list string) (rv []int) {
 	}
 	return
 }
-
-func Sum(l []int) (rv int) {
-	for _, n := range l {
-		rv += n
-	}
-	return
-}
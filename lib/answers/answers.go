@@ -0,0 +1,174 @@
+// Package answers keeps a ledger of what is known about each puzzle's
+// answer: the confirmed answer once it has been accepted, and the guesses
+// that were rejected along the way (with the "too high" / "too low" hint the
+// site gave, if any).
+//
+// Each day keeps its ledger in a file called "answers" next to its input,
+// one entry per line:
+//
+//	# comments and blank lines are ignored
+//	1 too high 100724
+//	1 too low 90818
+//	1 answer 92758
+//	2 wrong 21356
+//
+// New results are checked against the ledger so that a value contradicting
+// a known bound is flagged before it is submitted.
+package answers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The name of the ledger file in each day's directory.
+const Filename = "answers"
+
+type Verdict int
+
+const (
+	ANSWER Verdict = iota
+	TOO_HIGH
+	TOO_LOW
+	WRONG
+)
+
+var verdicts = map[Verdict]string{
+	ANSWER:   "answer",
+	TOO_HIGH: "too high",
+	TOO_LOW:  "too low",
+	WRONG:    "wrong",
+}
+
+func (v Verdict) String() string {
+	return verdicts[v]
+}
+
+func NewVerdict(s string) (Verdict, error) {
+	s = strings.ReplaceAll(strings.ToLower(s), "-", " ")
+	for v, vs := range verdicts {
+		if s == vs {
+			return v, nil
+		}
+	}
+	return WRONG, fmt.Errorf("unknown verdict %q", s)
+}
+
+// Entry is one line of the ledger.
+type Entry struct {
+	Part    int
+	Verdict Verdict
+	Value   string
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%d %s %s", e.Part, e.Verdict, e.Value)
+}
+
+func NewEntry(line string) (e Entry, err error) {
+	part, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	e.Part, err = strconv.Atoi(part)
+	if err != nil || e.Part < 1 || e.Part > 2 {
+		return e, fmt.Errorf("bad part in %q", line)
+	}
+	i := strings.LastIndex(rest, " ")
+	if i == -1 {
+		return e, fmt.Errorf("missing value in %q", line)
+	}
+	e.Value = rest[i+1:]
+	e.Verdict, err = NewVerdict(strings.TrimSpace(rest[:i]))
+	return
+}
+
+type Ledger struct {
+	Entries []Entry
+}
+
+// Reads a ledger, a missing file is an empty ledger.
+func NewLedger(filename string) (l Ledger, err error) {
+	f, err := os.OpenFile(filename, os.O_RDONLY, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := NewEntry(line)
+		if err != nil {
+			return l, fmt.Errorf("%s:%d: %w", filename, n, err)
+		}
+		l.Entries = append(l.Entries, e)
+	}
+	return l, s.Err()
+}
+
+// Appends an entry to the ledger file, creating it if needed.
+func Record(filename string, e Entry) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, e); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Returns the confirmed answer for part, if there is one.
+func (l Ledger) Answer(part int) (string, bool) {
+	for _, e := range l.Entries {
+		if e.Part == part && e.Verdict == ANSWER {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+// Check compares value against what is known about part. confirmed is set
+// when value is the recorded answer, err explains why value can't be right.
+// A value the ledger knows nothing about is neither.
+func (l Ledger) Check(part int, value string) (confirmed bool, err error) {
+	if answer, found := l.Answer(part); found {
+		if value == answer {
+			return true, nil
+		}
+		return false, fmt.Errorf("%s is not the answer %s", value, answer)
+	}
+
+	v, numeric := new(big.Int).SetString(value, 10)
+	for _, e := range l.Entries {
+		if e.Part != part {
+			continue
+		}
+		if value == e.Value {
+			return false, fmt.Errorf("%s was already guessed (%s)", value, e.Verdict)
+		}
+		if !numeric {
+			continue
+		}
+		guess, ok := new(big.Int).SetString(e.Value, 10)
+		if !ok {
+			continue
+		}
+		if e.Verdict == TOO_HIGH && v.Cmp(guess) > 0 {
+			return false, fmt.Errorf("%s is too high, %s already was", value, e.Value)
+		}
+		if e.Verdict == TOO_LOW && v.Cmp(guess) < 0 {
+			return false, fmt.Errorf("%s is too low, %s already was", value, e.Value)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package answers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewEntry(t *testing.T) {
+	e, err := NewEntry("1 too high 100724")
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Part: 1, Verdict: TOO_HIGH, Value: "100724"}, e)
+	assert.Equal(t, "1 too high 100724", e.String())
+
+	e, err = NewEntry("2 too-low 5")
+	require.NoError(t, err)
+	assert.Equal(t, TOO_LOW, e.Verdict)
+
+	for _, bad := range []string{"3 answer 1", "x answer 1", "1 answer", "1 maybe 4"} {
+		_, err = NewEntry(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func Test_Check(t *testing.T) {
+	l := Ledger{Entries: []Entry{
+		{1, TOO_HIGH, "100724"},
+		{1, TOO_HIGH, "93240"},
+		{1, TOO_LOW, "90818"},
+		{2, WRONG, "EFGH"},
+		{2, ANSWER, "ABCD"},
+	}}
+
+	for v, msg := range map[string]string{
+		"93240":  "already guessed",
+		"93241":  "too high",
+		"100":    "too low",
+		"1":      "too low",
+		"999999": "too high",
+	} {
+		_, err := l.Check(1, v)
+		assert.ErrorContains(t, err, msg, v)
+	}
+	confirmed, err := l.Check(1, "92758")
+	assert.NoError(t, err)
+	assert.False(t, confirmed)
+
+	confirmed, err = l.Check(2, "ABCD")
+	assert.NoError(t, err)
+	assert.True(t, confirmed)
+	_, err = l.Check(2, "WXYZ")
+	assert.ErrorContains(t, err, "not the answer ABCD")
+}
+
+func Test_Ledger(t *testing.T) {
+	f := filepath.Join(t.TempDir(), Filename)
+	l, err := NewLedger(f)
+	require.NoError(t, err)
+	assert.Empty(t, l.Entries)
+
+	require.NoError(t, os.WriteFile(f, []byte("# day 18\n1 too high 100724\n\n"), 0644))
+	require.NoError(t, Record(f, Entry{1, ANSWER, "92758"}))
+	l, err = NewLedger(f)
+	require.NoError(t, err)
+	assert.Len(t, l.Entries, 2)
+	a, found := l.Answer(1)
+	assert.True(t, found)
+	assert.Equal(t, "92758", a)
+	_, found = l.Answer(2)
+	assert.False(t, found)
+
+	require.NoError(t, os.WriteFile(f, []byte("1 too high 1\nnonsense\n"), 0644))
+	_, err = NewLedger(f)
+	assert.ErrorContains(t, err, ":2:")
+}
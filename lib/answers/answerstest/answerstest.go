@@ -0,0 +1,43 @@
+// Package answerstest checks results against a day's answers ledger from
+// its tests. It is kept apart from package answers so that programs using
+// the ledger don't link in the testing package.
+package answerstest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattbnz/aoc/lib/answers"
+)
+
+// Assert fails t if value contradicts the ledger in the current directory
+// (where go test runs each day's tests).
+func Assert(t testing.TB, part int, value any) {
+	t.Helper()
+	l, err := answers.NewLedger(answers.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmed, err := l.Check(part, fmt.Sprint(value))
+	if err != nil {
+		t.Errorf("part %d: %v", part, err)
+	} else if confirmed {
+		t.Logf("part %d: %v is the confirmed answer", part, value)
+	}
+}
+
+// AssertWrong fails t unless the ledger in the current directory already
+// rules value out, for keeping earlier attempts known to give a wrong
+// answer honest.
+func AssertWrong(t testing.TB, part int, value any) {
+	t.Helper()
+	l, err := answers.NewLedger(answers.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Check(part, fmt.Sprint(value)); err == nil {
+		t.Errorf("part %d: %v is expected to be wrong, but the ledger allows it", part, value)
+	} else {
+		t.Logf("part %d: %v is known to be wrong: %v", part, value, err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package cycle runs a simulation for a (very) large number of iterations by
+// spotting when its state starts repeating and skipping the repeats.
+package cycle
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+// Loop describes the repeating part of a simulation.
+type Loop struct {
+	// Iterations before the state first enters the loop.
+	Prefix int
+	// Iterations in one pass around the loop, 0 if no loop was found.
+	Period int
+	// Iterations actually stepped through to reach the requested count.
+	Steps int
+}
+
+func (l Loop) String() string {
+	if l.Period == 0 {
+		return fmt.Sprintf("no loop after %d steps", l.Steps)
+	}
+	return fmt.Sprintf("loop of %d after %d (took %d steps)", l.Period, l.Prefix, l.Steps)
+}
+
+// Found reports whether a loop was detected.
+func (l Loop) Found() bool {
+	return l.Period > 0
+}
+
+// Index maps iteration n onto the first iteration with the same state, i.e.
+// into the range [0, Prefix+Period).
+func (l Loop) Index(n int) int {
+	if l.Period == 0 || n < l.Prefix {
+		return n
+	}
+	return l.Prefix + (n-l.Prefix)%l.Period
+}
+
+// Run calls step until the simulation has advanced n iterations, leaving it
+// in the state it would have after n calls.
+//
+// key is called before the first step and after every step, and must return
+// the same value for the same state. Once a key repeats, the remaining whole
+// loops are skipped and only the leftover partial loop is stepped through.
+func Run[K comparable](n int, key func() K, step func()) (l Loop) {
+	seen := map[K]int{}
+	for i := 0; i < n; i++ {
+		k := key()
+		if first, found := seen[k]; found {
+			l.Prefix = first
+			l.Period = i - first
+			remaining := (n - i) % l.Period
+			glog.V(1).Infof("state at %d repeats %d, skipping ahead %d and stepping %d", i, first, n-i-remaining, remaining)
+			for j := 0; j < remaining; j++ {
+				step()
+				l.Steps++
+			}
+			return
+		}
+		seen[k] = i
+		step()
+		l.Steps++
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package cycle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// x -> (x*x + 1) % 255 from 0 goes 0,1,2,5,26,167,95,101,2,... so loops back
+// to the state after step 2 every 6 steps.
+type sim struct {
+	x     int
+	steps int
+}
+
+func (s *sim) key() int { return s.x }
+func (s *sim) step() {
+	s.x = (s.x*s.x + 1) % 255
+	s.steps++
+}
+
+func brute(n int) int {
+	s := sim{}
+	for i := 0; i < n; i++ {
+		s.step()
+	}
+	return s.x
+}
+
+func Test_Run(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		s := sim{}
+		Run(n, s.key, s.step)
+		assert.Equal(t, brute(n), s.x, "n=%d", n)
+	}
+}
+
+func Test_Loop(t *testing.T) {
+	s := sim{}
+	l := Run(1000000000, s.key, s.step)
+	assert.True(t, l.Found())
+	assert.Equal(t, 2, l.Prefix)
+	assert.Equal(t, 6, l.Period)
+	assert.Equal(t, s.steps, l.Steps)
+	assert.Less(t, l.Steps, 2+6+6)
+	assert.Equal(t, brute(l.Index(1000000000)), s.x)
+	assert.Equal(t, 1, l.Index(1))
+	assert.Equal(t, 2, l.Index(8))
+	assert.Equal(t, "loop of 6 after 2 (took 10 steps)", l.String())
+}
+
+func Test_NoLoop(t *testing.T) {
+	n := 0
+	l := Run(5, func() int { return n }, func() { n++ })
+	assert.False(t, l.Found())
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 5, l.Index(5))
+	assert.Equal(t, "no loop after 5 steps", l.String())
+}
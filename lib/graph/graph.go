@@ -0,0 +1,128 @@
+// Package graph describes small graphs (valve networks, node maps, workflow
+// trees) so they can be looked at, either as DOT text for graphviz or as a
+// self-contained SVG laid out here without any external tools.
+package graph
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Node struct {
+	ID    string
+	Label string
+}
+
+type Edge struct {
+	From, To string
+	Label    string
+}
+
+// Graph is a directed graph, nodes and edges keep the order they were added
+// in, which is also the order they are laid out in.
+type Graph struct {
+	Name  string
+	Nodes []Node
+	Edges []Edge
+
+	index map[string]int
+}
+
+func New(name string) *Graph {
+	return &Graph{Name: name, index: map[string]int{}}
+}
+
+// Adds a node, or updates the label of an existing one. An empty label
+// shows the ID.
+func (g *Graph) AddNode(id, label string) {
+	if g.index == nil {
+		g.index = map[string]int{}
+	}
+	if n, found := g.index[id]; found {
+		if label != "" {
+			g.Nodes[n].Label = label
+		}
+		return
+	}
+	g.index[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, Node{ID: id, Label: label})
+}
+
+// Adds an edge, and any of its nodes that aren't already in the graph.
+func (g *Graph) AddEdge(from, to, label string) {
+	g.AddNode(from, "")
+	g.AddNode(to, "")
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Label: label})
+}
+
+// Rebuilds the index, for graphs whose Nodes and Edges were set directly.
+func (g *Graph) reindex() {
+	g.index = map[string]int{}
+	for n, node := range g.Nodes {
+		g.index[node.ID] = n
+	}
+	for _, e := range g.Edges {
+		g.AddNode(e.From, "")
+		g.AddNode(e.To, "")
+	}
+}
+
+func (n Node) label() string {
+	if n.Label == "" {
+		return n.ID
+	}
+	return n.Label
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Writes the graph in graphviz DOT format.
+func (g *Graph) DOT(w io.Writer) error {
+	g.reindex()
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Name))
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", quote(n.ID), quote(n.label()))
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", quote(e.From), quote(e.To), quote(e.Label))
+		} else {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", quote(e.From), quote(e.To))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) String() string {
+	var b strings.Builder
+	g.DOT(&b)
+	return b.String()
+}
+
+// Writes the graph to filename, as DOT or SVG depending on its extension.
+func (g *Graph) WriteFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(filename) {
+	case ".dot", ".gv":
+		err = g.DOT(f)
+	case ".svg":
+		err = g.SVG(f)
+	default:
+		err = fmt.Errorf("don't know how to write %s", filename)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sample() *Graph {
+	g := New("valves")
+	g.AddNode("AA", "AA flow=0")
+	g.AddEdge("AA", "DD", "")
+	g.AddEdge("AA", "BB", "")
+	g.AddEdge("DD", "AA", "")
+	g.AddEdge("BB", "CC", `a<"b"`)
+	g.AddEdge("CC", "CC", "")
+	g.AddNode("BB", "BB flow=13")
+	return g
+}
+
+func Test_DOT(t *testing.T) {
+	assert.Equal(t, `digraph "valves" {
+	"AA" [label="AA flow=0"];
+	"DD" [label="DD"];
+	"BB" [label="BB flow=13"];
+	"CC" [label="CC"];
+	"AA" -> "DD";
+	"AA" -> "BB";
+	"DD" -> "AA";
+	"BB" -> "CC" [label="a<\"b\""];
+	"CC" -> "CC";
+}
+`, sample().String())
+
+	// Graphs built without New still get their edge's nodes.
+	g := Graph{Edges: []Edge{{From: "x", To: "y"}}}
+	assert.Contains(t, g.String(), `"y" [label="y"];`)
+}
+
+func Test_Layers(t *testing.T) {
+	g := sample()
+	g.reindex()
+	assert.Equal(t, [][]int{{0}, {1, 2}, {3}}, g.layers())
+
+	// A pure cycle still gets laid out.
+	c := New("cycle")
+	c.AddEdge("a", "b", "")
+	c.AddEdge("b", "a", "")
+	c.reindex()
+	assert.Equal(t, [][]int{{0}, {1}}, c.layers())
+}
+
+func Test_SVG(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, sample().SVG(&b))
+	svg := b.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Equal(t, 4, strings.Count(svg, "<rect x="))
+	assert.Contains(t, svg, ">BB flow=13</text>")
+	assert.Contains(t, svg, ">a&lt;&#34;b&#34;</text>")
+
+	// Well formed XML.
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
+
+func Test_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	g := sample()
+	require.NoError(t, g.WriteFile(filepath.Join(dir, "g.svg")))
+	require.NoError(t, g.WriteFile(filepath.Join(dir, "g.dot")))
+	assert.Error(t, g.WriteFile(filepath.Join(dir, "g.png")))
+	dot, err := os.ReadFile(filepath.Join(dir, "g.dot"))
+	require.NoError(t, err)
+	assert.Equal(t, g.String(), string(dot))
+}
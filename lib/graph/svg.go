@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	nodeHeight = 30
+	charWidth  = 8
+	minWidth   = 40
+	gapX       = 30
+	gapY       = 70
+	margin     = 20
+)
+
+type box struct {
+	x, y, w int // x, y is the top left corner
+	layer   int
+}
+
+func (b box) cx() int { return b.x + b.w/2 }
+
+// Assigns each node a layer: its distance from the nearest node with
+// nothing leading into it (or from the first unreached node, for cycles).
+func (g *Graph) layers() [][]int {
+	out := make([][]int, len(g.Nodes))
+	in := make([]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		f, t := g.index[e.From], g.index[e.To]
+		out[f] = append(out[f], t)
+		in[t]++
+	}
+	layer := make([]int, len(g.Nodes))
+	for n := range layer {
+		layer[n] = -1
+	}
+	bfs := func(q []int) {
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, t := range out[n] {
+				if layer[t] == -1 {
+					layer[t] = layer[n] + 1
+					q = append(q, t)
+				}
+			}
+		}
+	}
+	roots := []int{}
+	for n := range g.Nodes {
+		if in[n] == 0 {
+			layer[n] = 0
+			roots = append(roots, n)
+		}
+	}
+	bfs(roots)
+	for n := range g.Nodes {
+		if layer[n] == -1 {
+			layer[n] = 0
+			bfs([]int{n})
+		}
+	}
+
+	rv := [][]int{}
+	for n, l := range layer {
+		for len(rv) <= l {
+			rv = append(rv, nil)
+		}
+		rv[l] = append(rv[l], n)
+	}
+	return rv
+}
+
+// Orders nodes within their layers to reduce crossings, by repeatedly
+// sorting each layer on the average position of its neighbours.
+func (g *Graph) order(layers [][]int) {
+	pos := make([]float64, len(g.Nodes))
+	update := func() {
+		for _, l := range layers {
+			for i, n := range l {
+				pos[n] = float64(i) / float64(len(l))
+			}
+		}
+	}
+	neighbours := make([][]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		f, t := g.index[e.From], g.index[e.To]
+		neighbours[f] = append(neighbours[f], t)
+		neighbours[t] = append(neighbours[t], f)
+	}
+	update()
+	for sweep := 0; sweep < 4; sweep++ {
+		for _, l := range layers {
+			bary := map[int]float64{}
+			for _, n := range l {
+				if len(neighbours[n]) == 0 {
+					bary[n] = pos[n]
+					continue
+				}
+				sum := 0.0
+				for _, o := range neighbours[n] {
+					sum += pos[o]
+				}
+				bary[n] = sum / float64(len(neighbours[n]))
+			}
+			slices.SortStableFunc(l, func(a, b int) int {
+				switch {
+				case bary[a] < bary[b]:
+					return -1
+				case bary[a] > bary[b]:
+					return 1
+				}
+				return 0
+			})
+			update()
+		}
+	}
+}
+
+func (g *Graph) layout() (boxes []box, width, height int) {
+	layers := g.layers()
+	g.order(layers)
+
+	boxes = make([]box, len(g.Nodes))
+	widths := make([]int, len(layers))
+	for l, nodes := range layers {
+		for _, n := range nodes {
+			boxes[n].w = max(minWidth, charWidth*len(g.Nodes[n].label())+16)
+			boxes[n].layer = l
+			widths[l] += boxes[n].w + gapX
+		}
+		widths[l] -= gapX
+		width = max(width, widths[l])
+	}
+	for l, nodes := range layers {
+		x := margin + (width-widths[l])/2
+		for _, n := range nodes {
+			boxes[n].x = x
+			boxes[n].y = margin + l*(nodeHeight+gapY)
+			x += boxes[n].w + gapX
+		}
+	}
+	// Room on the right for edges that curve round the side.
+	width += 2*margin + 2*gapX + 10*len(layers) + 60
+	return boxes, width, len(layers)*(nodeHeight+gapY) - gapY + 2*margin
+}
+
+// Writes the graph as an SVG image, laid out top to bottom in layers.
+func (g *Graph) SVG(w io.Writer) error {
+	g.reindex()
+	boxes, width, height := g.layout()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="13">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(g.Name))
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>` + "\n")
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+
+	for _, e := range g.Edges {
+		fi, ti := g.index[e.From], g.index[e.To]
+		f, t := boxes[fi], boxes[ti]
+		var lx, ly int
+		switch {
+		case fi == ti:
+			// Self loop, out of the right hand side and back in.
+			x, y := f.x+f.w, f.y+nodeHeight/2
+			fmt.Fprintf(&b, `<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="black" marker-end="url(#arrow)"/>`+"\n",
+				x, y-8, x+30, y-20, x+30, y+20, x, y+8)
+			lx, ly = x+32, y
+		case t.layer > f.layer:
+			x1, y1, x2, y2 := f.cx(), f.y+nodeHeight, t.cx(), t.y
+			fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" marker-end="url(#arrow)"/>`+"\n", x1, y1, x2, y2)
+			lx, ly = (x1+x2)/2, (y1+y2)/2
+		default:
+			// Back or sideways edge, curve out to the side so it doesn't
+			// run through the nodes in between.
+			x1, y1, x2, y2 := f.x+f.w, f.y+nodeHeight/2, t.x+t.w, t.y+nodeHeight/2
+			bulge := max(x1, x2) + gapX + 10*(f.layer-t.layer)
+			fmt.Fprintf(&b, `<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="gray" marker-end="url(#arrow)"/>`+"\n",
+				x1, y1, bulge, y1, bulge, y2, x2, y2)
+			lx, ly = bulge-gapX/2, (y1+y2)/2
+		}
+		if e.Label != "" {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="blue" font-size="11">%s</text>`+"\n", lx+3, ly, html.EscapeString(e.Label))
+		}
+	}
+	for n, node := range g.Nodes {
+		bx := boxes[n]
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="#eef" stroke="black"/>`+"\n",
+			bx.x, bx.y, bx.w, nodeHeight)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+			bx.cx(), bx.y+nodeHeight/2, html.EscapeString(node.label()))
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,196 @@
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// FlexGrid can have indices below 1 vs Grid.
+//
+// It is sparse, positions that have never been set return the zero value of
+// C, and the bounds grow to cover every position passed to SetC.
+type FlexGrid[C Cell[C]] struct {
+	c              map[Pos]C
+	minrow, mincol int
+	maxrow, maxcol int
+}
+
+func (g FlexGrid[C]) String() string {
+	if g.maxrow < g.minrow {
+		return "Grid of 0x0"
+	}
+	return fmt.Sprintf("Grid of %dx%d", g.maxrow-g.minrow, g.maxcol-g.mincol)
+}
+
+func (g FlexGrid[C]) MinRow() int { return g.minrow }
+func (g FlexGrid[C]) MinCol() int { return g.mincol }
+func (g FlexGrid[C]) MaxRow() int { return g.maxrow }
+func (g FlexGrid[C]) MaxCol() int { return g.maxcol }
+
+// Whether p is within the current bounds of the grid.
+func (g FlexGrid[C]) In(p Pos) bool {
+	return p.Row >= g.minrow && p.Col >= g.mincol && p.Row <= g.maxrow && p.Col <= g.maxcol
+}
+
+func (g FlexGrid[C]) C(p Pos) C {
+	return g.c[p]
+}
+
+// Whether a cell has been set at p.
+func (g FlexGrid[C]) Has(p Pos) bool {
+	_, found := g.c[p]
+	return found
+}
+
+func (g *FlexGrid[C]) SetC(p Pos, v C) {
+	g.c[p] = v
+	if p.Row < g.minrow {
+		g.minrow = p.Row
+	}
+	if p.Row > g.maxrow {
+		g.maxrow = p.Row
+	}
+	if p.Col < g.mincol {
+		g.mincol = p.Col
+	}
+	if p.Col > g.maxcol {
+		g.maxcol = p.Col
+	}
+}
+
+func (g FlexGrid[C]) Next(p Pos, dir CardinalDirection) (np Pos, c C, found bool) {
+	np = p.Move(dir)
+	if np == p || !g.In(np) {
+		found = false
+		return
+	}
+	found = true
+	c = g.C(np)
+	return
+}
+
+// Returns a copy of this grid at the current minute (doesn't preserve past minutes)
+//
+// Cells are copied by value, so grids of pointer cells will share them.
+func (g FlexGrid[C]) Copy() FlexGrid[C] {
+	ng := FlexGrid[C]{
+		minrow: g.minrow,
+		maxrow: g.maxrow,
+		mincol: g.mincol,
+		maxcol: g.maxcol,
+		c:      map[Pos]C{},
+	}
+	for p, c := range g.c {
+		ng.c[p] = c
+	}
+	return ng
+}
+
+func (g FlexGrid[C]) print(p Pos) {
+	if !g.Has(p) {
+		fmt.Print(".")
+	} else {
+		fmt.Print(g.C(p))
+	}
+}
+
+func (g FlexGrid[C]) Print() {
+	for row := g.minrow; row <= g.maxrow; row++ {
+		for col := g.mincol; col <= g.maxcol; col++ {
+			g.print(Pos{row, col})
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (g FlexGrid[C]) PrintNumbered() {
+	fmt.Print("X")
+	for col := g.mincol; col <= g.maxcol; col++ {
+		fmt.Printf("%d", abs(col)%10)
+	}
+	fmt.Println()
+	for row := g.minrow; row <= g.maxrow; row++ {
+		fmt.Printf("%d", abs(row)%10)
+		for col := g.mincol; col <= g.maxcol; col++ {
+			g.print(Pos{row, col})
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (g FlexGrid[C]) Each(cb func(Pos, C) bool) {
+	for row := g.minrow; row <= g.maxrow; row++ {
+		for col := g.mincol; col <= g.maxcol; col++ {
+			p := Pos{row, col}
+			if !cb(p, g.C(p)) {
+				return
+			}
+		}
+	}
+}
+
+// Cells are compared by their String() representation, a cell that is set
+// never equals one that isn't.
+func (g FlexGrid[C]) Equal(o FlexGrid[C]) bool {
+	if g.minrow != o.minrow || g.mincol != o.mincol || g.maxrow != o.maxrow || g.maxcol != o.maxcol {
+		return false
+	}
+
+	for row := g.minrow; row <= g.maxrow; row++ {
+		for col := g.mincol; col <= g.maxcol; col++ {
+			p := Pos{row, col}
+			if g.Has(p) != o.Has(p) {
+				return false
+			}
+			if g.Has(p) && g.C(p).String() != o.C(p).String() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns a grid with no cells, whose bounds will be set by the first SetC.
+func NewFlexGrid[C Cell[C]]() (g FlexGrid[C]) {
+	g.c = map[Pos]C{}
+	g.minrow = math.MaxInt
+	g.mincol = math.MaxInt
+	g.maxrow = math.MinInt
+	g.maxcol = math.MinInt
+	return
+}
+
+func NewFlexGridFromReader[C Cell[C]](r io.Reader) FlexGrid[C] {
+	return NewFlexGridFromScanner[C](bufio.NewScanner(r))
+}
+
+// As NewGridFromScanner, but returning a FlexGrid.
+func NewFlexGridFromScanner[C Cell[C]](s *bufio.Scanner) FlexGrid[C] {
+	g := NewFlexGrid[C]()
+
+	var cFactory C
+
+	row := 1
+	for s.Scan() {
+		if s.Text() == "" {
+			return g
+		}
+		for col, cStr := range s.Text() {
+			p := Pos{row, col + 1}
+			g.SetC(p, cFactory.New(string(cStr), p))
+		}
+		row++
+	}
+	return g
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return x * -1
+	}
+	return x
+}
@@ -1,4 +1,10 @@
-package day16
+// Package grid holds the 2D grid types shared by the puzzles that read a
+// map of characters as input.
+//
+// Grid is bounded and 1-indexed, FlexGrid is sparse and can grow in any
+// direction (including below 1). Both are generic over their cell type so
+// callers get their own cells back without type assertions.
+package grid
 
 import (
 	"bufio"
@@ -10,15 +16,30 @@ import (
 
 // 1 Based row, col indices
 type Pos struct {
-	row, col int
+	Row, Col int
 }
 
 func (p Pos) String() string {
-	return fmt.Sprintf("%d,%d", p.row, p.col)
+	return fmt.Sprintf("%d,%d", p.Row, p.Col)
 }
 
 func (p Pos) IsZero() bool {
-	return p.row == 0 && p.col == 0
+	return p.Row == 0 && p.Col == 0
+}
+
+// Returns the position one step away in dir (or p if dir is not a direction).
+func (p Pos) Move(dir CardinalDirection) Pos {
+	switch dir {
+	case NORTH:
+		return Pos{p.Row - 1, p.Col}
+	case SOUTH:
+		return Pos{p.Row + 1, p.Col}
+	case EAST:
+		return Pos{p.Row, p.Col + 1}
+	case WEST:
+		return Pos{p.Row, p.Col - 1}
+	}
+	return p
 }
 
 type CardinalDirection int
@@ -73,59 +94,62 @@ func NewCardinalDirection(s string) (CardinalDirection, error) {
 	return NORTH, fmt.Errorf("%s is not a cardinal direction", s)
 }
 
-type Cell interface {
+// Cell is implemented by the type stored in each grid position. C is the
+// implementing type itself, so that New hands back a typed cell.
+type Cell[C any] interface {
 	// Prints a representation of the cell.
 	String() string
 
 	// Returns a new instance of Cell based on the given string.
-	New(string, Pos) Cell
+	New(string, Pos) C
 }
 
 type BaseCell struct {
-	id     Pos
+	ID     Pos
 	Symbol string
 }
 
+var _ Cell[BaseCell] = BaseCell{}
+
 func (c BaseCell) String() string {
 	return c.Symbol
 }
 
-func (c BaseCell) New(s string, p Pos) Cell {
-	return BaseCell{Symbol: s, id: p}
+func (c BaseCell) New(s string, p Pos) BaseCell {
+	return BaseCell{Symbol: s, ID: p}
 }
 
-type Grid struct {
-	c              map[Pos]Cell
+// Grid is bounded, with rows and cols from 1 to MaxRow/MaxCol.
+type Grid[C Cell[C]] struct {
+	c              map[Pos]C
 	maxrow, maxcol int
 }
 
-func (g Grid) String() string {
+func (g Grid[C]) String() string {
 	return fmt.Sprintf("Grid of %dx%d", g.maxrow, g.maxcol)
 }
 
-func (g Grid) C(p Pos) Cell {
+func (g Grid[C]) MinRow() int { return 1 }
+func (g Grid[C]) MinCol() int { return 1 }
+func (g Grid[C]) MaxRow() int { return g.maxrow }
+func (g Grid[C]) MaxCol() int { return g.maxcol }
+
+// Whether p is within the bounds of the grid.
+func (g Grid[C]) In(p Pos) bool {
+	return p.Row >= 1 && p.Col >= 1 && p.Row <= g.maxrow && p.Col <= g.maxcol
+}
+
+func (g Grid[C]) C(p Pos) C {
 	return g.c[p]
 }
 
-func (g Grid) SetC(p Pos, v Cell) {
+func (g *Grid[C]) SetC(p Pos, v C) {
 	g.c[p] = v
 }
 
-func (g Grid) Next(p Pos, dir CardinalDirection) (np Pos, c Cell, found bool) {
-	switch dir {
-	case NORTH:
-		np.row, np.col = p.row-1, p.col
-	case SOUTH:
-		np.row, np.col = p.row+1, p.col
-	case EAST:
-		np.row, np.col = p.row, p.col+1
-	case WEST:
-		np.row, np.col = p.row, p.col-1
-	default:
-		found = false
-		return
-	}
-	if np.row < 1 || np.col < 1 || np.row > g.maxrow || np.col > g.maxcol {
+func (g Grid[C]) Next(p Pos, dir CardinalDirection) (np Pos, c C, found bool) {
+	np = p.Move(dir)
+	if np == p || !g.In(np) {
 		found = false
 		return
 	}
@@ -135,11 +159,13 @@ func (g Grid) Next(p Pos, dir CardinalDirection) (np Pos, c Cell, found bool) {
 }
 
 // Returns a copy of this grid at the current minute (doesn't preserve past minutes)
-func (g Grid) Copy() Grid {
-	ng := Grid{
+//
+// Cells are copied by value, so grids of pointer cells will share them.
+func (g Grid[C]) Copy() Grid[C] {
+	ng := Grid[C]{
 		maxrow: g.maxrow,
 		maxcol: g.maxcol,
-		c:      map[Pos]Cell{},
+		c:      map[Pos]C{},
 	}
 	for p, c := range g.c {
 		ng.c[p] = c
@@ -147,7 +173,7 @@ func (g Grid) Copy() Grid {
 	return ng
 }
 
-func (g Grid) Print() {
+func (g Grid[C]) Print() {
 	for row := 1; row <= g.maxrow; row++ {
 		for col := 1; col <= g.maxcol; col++ {
 			fmt.Print(g.C(Pos{row, col}))
@@ -157,7 +183,7 @@ func (g Grid) Print() {
 	fmt.Println()
 }
 
-func (g Grid) PrintNumbered() {
+func (g Grid[C]) PrintNumbered() {
 	fmt.Print("X")
 	for col := 1; col <= g.maxcol; col++ {
 		fmt.Printf("%d", col%10)
@@ -173,7 +199,7 @@ func (g Grid) PrintNumbered() {
 	fmt.Println()
 }
 
-func (g Grid) Each(cb func(Pos, Cell) bool) {
+func (g Grid[C]) Each(cb func(Pos, C) bool) {
 	for row := 1; row <= g.maxrow; row++ {
 		for col := 1; col <= g.maxcol; col++ {
 			p := Pos{row, col}
@@ -184,7 +210,8 @@ func (g Grid) Each(cb func(Pos, Cell) bool) {
 	}
 }
 
-func (g Grid) Equal(o Grid) bool {
+// Cells are compared by their String() representation.
+func (g Grid[C]) Equal(o Grid[C]) bool {
 	if g.maxrow != o.maxrow || g.maxcol != o.maxcol {
 		return false
 	}
@@ -192,7 +219,7 @@ func (g Grid) Equal(o Grid) bool {
 	for row := 1; row <= g.maxrow; row++ {
 		for col := 1; col <= g.maxcol; col++ {
 			p := Pos{row, col}
-			if g.C(p) != o.C(p) {
+			if g.C(p).String() != o.C(p).String() {
 				return false
 			}
 		}
@@ -200,14 +227,15 @@ func (g Grid) Equal(o Grid) bool {
 	return true
 }
 
-func (g *Grid) DupRow(r int) bool {
+// Inserts a copy of row r below it, shifting the following rows down.
+//
+// Cells are re-created (via New) from their String() at their new position.
+func (g *Grid[C]) DupRow(r int) bool {
 	for row := g.maxrow; row >= r; row-- {
 		for col := 1; col <= g.maxcol; col++ {
 			p := Pos{row, col}
 			np := Pos{row + 1, col}
-			newC := g.C(p).(BaseCell)
-			newC.id = np
-			g.c[np] = newC
+			g.c[np] = g.c[p].New(g.c[p].String(), np)
 		}
 	}
 	g.maxrow++
@@ -215,14 +243,15 @@ func (g *Grid) DupRow(r int) bool {
 	return true
 }
 
-func (g *Grid) DupCol(c int) bool {
+// Inserts a copy of col c to its right, shifting the following cols along.
+//
+// Cells are re-created (via New) from their String() at their new position.
+func (g *Grid[C]) DupCol(c int) bool {
 	for col := g.maxcol; col >= c; col-- {
 		for row := 1; row <= g.maxrow; row++ {
 			p := Pos{row, col}
 			np := Pos{row, col + 1}
-			newC := g.C(p).(BaseCell)
-			newC.id = np
-			g.c[np] = newC
+			g.c[np] = g.c[p].New(g.c[p].String(), np)
 		}
 	}
 	g.maxcol++
@@ -230,12 +259,14 @@ func (g *Grid) DupCol(c int) bool {
 	return true
 }
 
-func NewGrid[C Cell](r io.Reader) Grid {
+func NewGrid[C Cell[C]](r io.Reader) Grid[C] {
 	return NewGridFromScanner[C](bufio.NewScanner(r))
 }
 
-func NewGridFromScanner[C Cell](s *bufio.Scanner) Grid {
-	g := Grid{c: map[Pos]Cell{}}
+// Reads a grid from s, stopping at the first blank line so that several
+// grids can be read from the same input. MaxRow is -1 if no rows were read.
+func NewGridFromScanner[C Cell[C]](s *bufio.Scanner) Grid[C] {
+	g := Grid[C]{c: map[Pos]C{}}
 	g.maxrow = -1
 	g.maxcol = -1
 
@@ -248,11 +279,10 @@ func NewGridFromScanner[C Cell](s *bufio.Scanner) Grid {
 		}
 		for col, cStr := range s.Text() {
 			p := Pos{row, col + 1}
-			c := cFactory.New(string(cStr), p)
-			g.c[p] = c
-			g.maxcol = Max(g.maxcol, col+1)
+			g.c[p] = cFactory.New(string(cStr), p)
+			g.maxcol = max(g.maxcol, col+1)
 		}
-		g.maxrow = Max(g.maxrow, row)
+		g.maxrow = max(g.maxrow, row)
 		row++
 	}
 	return g
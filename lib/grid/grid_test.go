@@ -0,0 +1,142 @@
+package grid
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `#..
+.#.
+..#
+
+ab
+cd
+`
+
+func Test_NewGrid(t *testing.T) {
+	s := bufio.NewScanner(bytes.NewBufferString(sample))
+	g := NewGridFromScanner[BaseCell](s)
+	assert.Equal(t, 3, g.MaxRow())
+	assert.Equal(t, 3, g.MaxCol())
+	assert.Equal(t, "#", g.C(Pos{1, 1}).Symbol)
+	assert.Equal(t, Pos{3, 3}, g.C(Pos{3, 3}).ID)
+
+	g2 := NewGridFromScanner[BaseCell](s)
+	assert.Equal(t, 2, g2.MaxRow())
+	assert.Equal(t, "d", g2.C(Pos{2, 2}).Symbol)
+
+	g3 := NewGridFromScanner[BaseCell](s)
+	assert.Equal(t, -1, g3.MaxRow())
+}
+
+func Test_Next(t *testing.T) {
+	g := NewGrid[BaseCell](bytes.NewBufferString(sample))
+	np, c, found := g.Next(Pos{1, 1}, EAST)
+	require.True(t, found)
+	assert.Equal(t, Pos{1, 2}, np)
+	assert.Equal(t, ".", c.Symbol)
+
+	_, _, found = g.Next(Pos{1, 1}, NORTH)
+	assert.False(t, found)
+	_, _, found = g.Next(Pos{3, 3}, EAST)
+	assert.False(t, found)
+	_, _, found = g.Next(Pos{2, 2}, NO_DIRECTION)
+	assert.False(t, found)
+
+	for _, d := range CardinalDirections {
+		assert.Equal(t, Pos{2, 2}, Pos{2, 2}.Move(d).Move(d.Opposite()))
+	}
+}
+
+func Test_CopyEqual(t *testing.T) {
+	g := NewGrid[BaseCell](bytes.NewBufferString(sample))
+	g2 := g.Copy()
+	assert.True(t, g.Equal(g2))
+	g2.SetC(Pos{1, 1}, BaseCell{ID: Pos{1, 1}, Symbol: "."})
+	assert.False(t, g.Equal(g2))
+	assert.Equal(t, "#", g.C(Pos{1, 1}).Symbol)
+}
+
+func Test_Dup(t *testing.T) {
+	g := NewGrid[BaseCell](bytes.NewBufferString("ab\ncd\n"))
+	g.DupRow(1)
+	g.DupCol(2)
+	expected := NewGrid[BaseCell](bytes.NewBufferString("abb\nabb\ncdd\n"))
+	assert.True(t, expected.Equal(g))
+	assert.Equal(t, Pos{3, 3}, g.C(Pos{3, 3}).ID)
+}
+
+// Pointer cells must come back typed, without assertions.
+type countCell struct {
+	BaseCell
+	n int
+}
+
+func (c *countCell) New(s string, p Pos) *countCell {
+	return &countCell{BaseCell: BaseCell{ID: p, Symbol: s}}
+}
+
+func Test_TypedCells(t *testing.T) {
+	g := NewGrid[*countCell](bytes.NewBufferString(sample))
+	g.Each(func(p Pos, c *countCell) bool {
+		c.n = p.Row * p.Col
+		return true
+	})
+	assert.Equal(t, 6, g.C(Pos{2, 3}).n)
+}
+
+func Test_FlexGrid(t *testing.T) {
+	g := NewFlexGrid[BaseCell]()
+	assert.False(t, g.In(Pos{0, 0}))
+
+	g.SetC(Pos{0, 0}, BaseCell{Symbol: "#"})
+	g.SetC(Pos{-2, 3}, BaseCell{Symbol: "#"})
+	assert.Equal(t, -2, g.MinRow())
+	assert.Equal(t, 0, g.MinCol())
+	assert.Equal(t, 0, g.MaxRow())
+	assert.Equal(t, 3, g.MaxCol())
+	assert.True(t, g.Has(Pos{-2, 3}))
+	assert.False(t, g.Has(Pos{-1, 1}))
+
+	np, c, found := g.Next(Pos{-1, 3}, NORTH)
+	require.True(t, found)
+	assert.Equal(t, Pos{-2, 3}, np)
+	assert.Equal(t, "#", c.Symbol)
+	_, _, found = g.Next(Pos{-2, 3}, NORTH)
+	assert.False(t, found)
+
+	n := 0
+	g.Each(func(Pos, BaseCell) bool {
+		n++
+		return true
+	})
+	assert.Equal(t, 12, n)
+
+	g2 := g.Copy()
+	assert.True(t, g.Equal(g2))
+	g2.SetC(Pos{-1, 1}, BaseCell{Symbol: "."})
+	assert.False(t, g.Equal(g2))
+	assert.False(t, g.Has(Pos{-1, 1}))
+
+	neg := NewFlexGrid[BaseCell]()
+	neg.SetC(Pos{-5, -3}, BaseCell{Symbol: "#"})
+	neg.SetC(Pos{-2, -7}, BaseCell{Symbol: "#"})
+	assert.Equal(t, -5, neg.MinRow())
+	assert.Equal(t, -7, neg.MinCol())
+	assert.Equal(t, -2, neg.MaxRow())
+	assert.Equal(t, -3, neg.MaxCol())
+	assert.True(t, neg.In(Pos{-2, -3}))
+}
+
+func Test_NewFlexGrid(t *testing.T) {
+	g := NewFlexGridFromReader[BaseCell](bytes.NewBufferString(sample))
+	assert.Equal(t, 1, g.MinRow())
+	assert.Equal(t, 1, g.MinCol())
+	assert.Equal(t, 3, g.MaxRow())
+	assert.Equal(t, 3, g.MaxCol())
+	assert.Equal(t, "#", g.C(Pos{2, 2}).Symbol)
+}
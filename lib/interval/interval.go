@@ -0,0 +1,177 @@
+// Package interval does set algebra on ranges of integers, for puzzles that
+// need to push whole ranges of ids through a calculation rather than one id
+// at a time.
+//
+// Intervals are half open, [Lo, Hi), which matches the "base + count" form
+// puzzles usually give them in. Use Closed for inclusive bounds.
+package interval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Interval struct {
+	Lo, Hi int
+}
+
+// Returns the interval of count ids starting at base.
+func FromCount(base, count int) Interval {
+	return Interval{base, base + count}
+}
+
+// Returns the interval from lo to hi, both included.
+func Closed(lo, hi int) Interval {
+	return Interval{lo, hi + 1}
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("[%d,%d)", i.Lo, i.Hi)
+}
+
+func (i Interval) Empty() bool {
+	return i.Hi <= i.Lo
+}
+
+func (i Interval) Len() int {
+	if i.Empty() {
+		return 0
+	}
+	return i.Hi - i.Lo
+}
+
+func (i Interval) Contains(x int) bool {
+	return x >= i.Lo && x < i.Hi
+}
+
+// Returns the overlap of i and o (which may be empty).
+func (i Interval) Intersect(o Interval) Interval {
+	return Interval{max(i.Lo, o.Lo), min(i.Hi, o.Hi)}
+}
+
+func (i Interval) Shift(d int) Interval {
+	return Interval{i.Lo + d, i.Hi + d}
+}
+
+// Set is a union of intervals, kept sorted with no overlapping or touching
+// members. The zero value is the empty set.
+type Set struct {
+	iv []Interval
+}
+
+// Returns the set covering all of the given intervals.
+func NewSet(intervals ...Interval) (s Set) {
+	for _, i := range intervals {
+		if !i.Empty() {
+			s.iv = append(s.iv, i)
+		}
+	}
+	slices.SortFunc(s.iv, func(a, b Interval) int { return a.Lo - b.Lo })
+	merged := []Interval{}
+	for _, i := range s.iv {
+		if n := len(merged); n > 0 && i.Lo <= merged[n-1].Hi {
+			merged[n-1].Hi = max(merged[n-1].Hi, i.Hi)
+			continue
+		}
+		merged = append(merged, i)
+	}
+	s.iv = merged
+	return
+}
+
+// The (sorted, disjoint) intervals making up the set.
+func (s Set) Intervals() []Interval {
+	return slices.Clone(s.iv)
+}
+
+func (s Set) String() string {
+	parts := []string{}
+	for _, i := range s.iv {
+		parts = append(parts, i.String())
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+func (s Set) Empty() bool {
+	return len(s.iv) == 0
+}
+
+// Number of ids in the set.
+func (s Set) Len() (rv int) {
+	for _, i := range s.iv {
+		rv += i.Len()
+	}
+	return
+}
+
+// Returns the lowest id in the set, which must not be empty.
+func (s Set) Min() int {
+	return s.iv[0].Lo
+}
+
+// Returns the highest id in the set, which must not be empty.
+func (s Set) Max() int {
+	return s.iv[len(s.iv)-1].Hi - 1
+}
+
+func (s Set) Contains(x int) bool {
+	i, found := slices.BinarySearchFunc(s.iv, x, func(i Interval, x int) int {
+		if i.Hi <= x {
+			return -1
+		} else if i.Lo > x {
+			return 1
+		}
+		return 0
+	})
+	return found && s.iv[i].Contains(x)
+}
+
+func (s Set) Union(o Set) Set {
+	return NewSet(append(slices.Clone(s.iv), o.iv...)...)
+}
+
+func (s Set) Intersect(o Set) Set {
+	rv := []Interval{}
+	for a, b := 0, 0; a < len(s.iv) && b < len(o.iv); {
+		if i := s.iv[a].Intersect(o.iv[b]); !i.Empty() {
+			rv = append(rv, i)
+		}
+		if s.iv[a].Hi < o.iv[b].Hi {
+			a++
+		} else {
+			b++
+		}
+	}
+	return NewSet(rv...)
+}
+
+// Returns the ids in s that are not in o.
+func (s Set) Difference(o Set) Set {
+	rv := []Interval{}
+	for _, i := range s.iv {
+		lo := i.Lo
+		for _, c := range o.iv {
+			if c.Hi <= lo || c.Lo >= i.Hi {
+				continue
+			}
+			if c.Lo > lo {
+				rv = append(rv, Interval{lo, c.Lo})
+			}
+			lo = max(lo, c.Hi)
+		}
+		if lo < i.Hi {
+			rv = append(rv, Interval{lo, i.Hi})
+		}
+	}
+	return NewSet(rv...)
+}
+
+// Returns the set with every id moved by d.
+func (s Set) Shift(d int) Set {
+	rv := Set{iv: make([]Interval, len(s.iv))}
+	for n, i := range s.iv {
+		rv.iv[n] = i.Shift(d)
+	}
+	return rv
+}
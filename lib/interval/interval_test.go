@@ -0,0 +1,118 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewSet(t *testing.T) {
+	s := NewSet(Interval{5, 8}, Closed(1, 2), FromCount(3, 2), Interval{9, 9}, Interval{7, 10})
+	assert.Equal(t, "{[1,3) [3,5) [5,10)}", Set{iv: []Interval{{1, 3}, {3, 5}, {5, 10}}}.String())
+	assert.Equal(t, "{[1,10)}", s.String())
+	assert.Equal(t, 9, s.Len())
+	assert.Equal(t, 1, s.Min())
+	assert.Equal(t, 9, s.Max())
+	assert.True(t, Set{}.Empty())
+}
+
+func Test_SetOps(t *testing.T) {
+	a := NewSet(Interval{0, 10}, Interval{20, 30})
+	b := NewSet(Interval{5, 25}, Interval{40, 41})
+
+	assert.Equal(t, NewSet(Interval{0, 30}, Interval{40, 41}), a.Union(b))
+	assert.Equal(t, NewSet(Interval{5, 10}, Interval{20, 25}), a.Intersect(b))
+	assert.Equal(t, NewSet(Interval{0, 5}, Interval{25, 30}), a.Difference(b))
+	assert.Equal(t, NewSet(Interval{10, 20}, Interval{40, 41}), b.Difference(a))
+	assert.Equal(t, NewSet(Interval{-5, 5}, Interval{15, 25}), a.Shift(-5))
+
+	assert.True(t, a.Contains(0))
+	assert.True(t, a.Contains(29))
+	assert.False(t, a.Contains(10))
+	assert.False(t, a.Contains(-1))
+}
+
+const size = 60
+
+func random(r *rand.Rand) (s Set, in [size]bool) {
+	ivs := []Interval{}
+	for n := r.Intn(4); n > 0; n-- {
+		lo := r.Intn(size)
+		i := Interval{lo, lo + r.Intn(size-lo) + 1}
+		ivs = append(ivs, i)
+		for x := i.Lo; x < i.Hi; x++ {
+			in[x] = true
+		}
+	}
+	return NewSet(ivs...), in
+}
+
+// Set operations agree with doing the same thing one id at a time.
+func Test_SetOpsBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		a, inA := random(r)
+		b, inB := random(r)
+		u, i, d := a.Union(b), a.Intersect(b), a.Difference(b)
+		for x := -1; x <= size; x++ {
+			xa := x >= 0 && x < size && inA[x]
+			xb := x >= 0 && x < size && inB[x]
+			require.Equal(t, xa || xb, u.Contains(x), "%s | %s @%d", a, b, x)
+			require.Equal(t, xa && xb, i.Contains(x), "%s & %s @%d", a, b, x)
+			require.Equal(t, xa && !xb, d.Contains(x), "%s - %s @%d", a, b, x)
+		}
+		assert.Equal(t, u, NewSet(u.Intervals()...))
+	}
+}
+
+func Test_Mapping(t *testing.T) {
+	// seed-to-soil from the 2023 day 5 sample.
+	m, err := NewMapping(Piece{FromCount(98, 2), 50 - 98}, Piece{FromCount(50, 48), 52 - 50})
+	require.NoError(t, err)
+	assert.Equal(t, 81, m.Map(79))
+	assert.Equal(t, 14, m.Map(14))
+	assert.Equal(t, 50, m.Map(98))
+	assert.Equal(t, "{[50,98)+2 [98,100)-48}", m.String())
+
+	assert.Equal(t, NewSet(Interval{50, 52}, Interval{98, 101}), m.Apply(NewSet(Interval{96, 101})))
+
+	_, err = NewMapping(Piece{Interval{0, 5}, 1}, Piece{Interval{4, 6}, 1})
+	assert.Error(t, err)
+}
+
+func randomMapping(r *rand.Rand) Mapping {
+	pieces := []Piece{}
+	lo := r.Intn(10)
+	for lo < size {
+		hi := lo + r.Intn(10) + 1
+		if r.Intn(3) > 0 {
+			pieces = append(pieces, Piece{Interval{lo, hi}, r.Intn(30) - 15})
+		}
+		lo = hi + r.Intn(5)
+	}
+	m, _ := NewMapping(pieces...)
+	return m
+}
+
+// Composition and Apply agree with mapping one id at a time.
+func Test_MappingBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 300; n++ {
+		a, b := randomMapping(r), randomMapping(r)
+		ab := a.Then(b)
+		s, _ := random(r)
+		img := ab.Apply(s)
+		want := []Interval{}
+		for x := -20; x < size+20; x++ {
+			require.Equal(t, b.Map(a.Map(x)), ab.Map(x), "%s then %s @%d", a, b, x)
+			if s.Contains(x) {
+				want = append(want, Interval{ab.Map(x), ab.Map(x) + 1})
+			}
+		}
+		require.Equal(t, NewSet(want...), img, "%s through %s", s, ab)
+		_, err := NewMapping(ab.Pieces()...)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,114 @@
+package interval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Piece moves the ids in From by Delta.
+type Piece struct {
+	From  Interval
+	Delta int
+}
+
+func (p Piece) String() string {
+	return fmt.Sprintf("%s%+d", p.From, p.Delta)
+}
+
+// Mapping is a piecewise shift of ids: ids inside one of its pieces move by
+// that piece's delta, all others map to themselves.
+type Mapping struct {
+	pieces []Piece
+}
+
+// Builds a mapping from pieces, which must not overlap.
+func NewMapping(pieces ...Piece) (m Mapping, err error) {
+	for _, p := range pieces {
+		if !p.From.Empty() {
+			m.pieces = append(m.pieces, p)
+		}
+	}
+	slices.SortFunc(m.pieces, func(a, b Piece) int { return a.From.Lo - b.From.Lo })
+	for n := 1; n < len(m.pieces); n++ {
+		if m.pieces[n].From.Lo < m.pieces[n-1].From.Hi {
+			return m, fmt.Errorf("pieces %s and %s overlap", m.pieces[n-1], m.pieces[n])
+		}
+	}
+	return
+}
+
+func (m Mapping) String() string {
+	parts := []string{}
+	for _, p := range m.pieces {
+		parts = append(parts, p.String())
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// The pieces of the mapping, sorted by where they map from.
+func (m Mapping) Pieces() []Piece {
+	return slices.Clone(m.pieces)
+}
+
+// Returns where the mapping sends x.
+func (m Mapping) Map(x int) int {
+	for _, p := range m.pieces {
+		if p.From.Contains(x) {
+			return x + p.Delta
+		}
+	}
+	return x
+}
+
+// Apply returns the image of the whole set s under the mapping.
+func (m Mapping) Apply(s Set) Set {
+	rv := []Interval{}
+	unmapped := s
+	for _, p := range m.pieces {
+		in := s.Intersect(NewSet(p.From))
+		for _, i := range in.iv {
+			rv = append(rv, i.Shift(p.Delta))
+		}
+		unmapped = unmapped.Difference(in)
+	}
+	return NewSet(append(rv, unmapped.iv...)...)
+}
+
+// Then returns the mapping that applies m, then o.
+func (m Mapping) Then(o Mapping) Mapping {
+	rv := []Piece{}
+	// Ids m moves: split each piece by where its image lands in o.
+	for _, p := range m.pieces {
+		img := p.From.Shift(p.Delta)
+		covered := Set{}
+		for _, q := range o.pieces {
+			if i := img.Intersect(q.From); !i.Empty() {
+				rv = append(rv, Piece{i.Shift(-p.Delta), p.Delta + q.Delta})
+				covered = covered.Union(NewSet(i))
+			}
+		}
+		for _, i := range NewSet(img).Difference(covered).iv {
+			rv = append(rv, Piece{i.Shift(-p.Delta), p.Delta})
+		}
+	}
+	// Ids m leaves alone only move if o moves them.
+	moved := Set{}
+	for _, p := range m.pieces {
+		moved = moved.Union(NewSet(p.From))
+	}
+	for _, q := range o.pieces {
+		for _, i := range NewSet(q.From).Difference(moved).iv {
+			rv = append(rv, Piece{i, q.Delta})
+		}
+	}
+
+	composed := Mapping{}
+	for _, p := range rv {
+		if p.Delta != 0 {
+			composed.pieces = append(composed.pieces, p)
+		}
+	}
+	slices.SortFunc(composed.pieces, func(a, b Piece) int { return a.From.Lo - b.From.Lo })
+	return composed
+}
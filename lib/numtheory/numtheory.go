@@ -0,0 +1,277 @@
+// Package numtheory has the number theory puzzles keep needing: gcd and
+// lcm, extended Euclid, modular arithmetic and the Chinese Remainder
+// Theorem, all watching for int overflow.
+package numtheory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var (
+	ErrOverflow   = errors.New("result overflows int")
+	ErrNoSolution = errors.New("no solution")
+)
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Greatest common divisor of a and b, always >= 0.
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return abs(a)
+}
+
+// Lowest common multiple of all the given numbers, or ErrOverflow.
+func LCM(nums ...int) (int, error) {
+	rv := 1
+	for _, n := range nums {
+		if n == 0 {
+			return 0, nil
+		}
+		v, ok := Mul(rv/GCD(rv, n), abs(n))
+		if !ok {
+			return 0, fmt.Errorf("lcm of %v: %w", nums, ErrOverflow)
+		}
+		rv = v
+	}
+	return rv, nil
+}
+
+// Lowest common multiple of all the given numbers, however big.
+func BigLCM(nums ...int) *big.Int {
+	rv := big.NewInt(1)
+	g := new(big.Int)
+	for _, n := range nums {
+		b := big.NewInt(int64(abs(n)))
+		if n == 0 {
+			return b
+		}
+		g.GCD(nil, nil, rv, b)
+		rv.Mul(rv.Div(rv, g), b)
+	}
+	return rv
+}
+
+// Returns g = gcd(a, b) and x, y such that a*x + b*y = g.
+func ExtGCD(a, b int) (g, x, y int) {
+	x0, x1, y0, y1 := 1, 0, 0, 1
+	for b != 0 {
+		q := a / b
+		a, b = b, a-q*b
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+	if a < 0 {
+		return -a, -x0, -y0
+	}
+	return a, x0, y0
+}
+
+// Returns a mod m, in [0, m).
+func Mod(a, m int) int {
+	a %= m
+	if a < 0 {
+		a += m
+	}
+	return a
+}
+
+// Returns x in [0, m) with a*x = 1 mod m, or ErrNoSolution if a and m
+// aren't coprime.
+func ModInverse(a, m int) (int, error) {
+	g, x, _ := ExtGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%d has no inverse mod %d: %w", a, m, ErrNoSolution)
+	}
+	return Mod(x, m), nil
+}
+
+// Returns a + b, and false if that overflowed.
+func Add(a, b int) (int, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+// Returns a * b, and false if that overflowed.
+func Mul(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	return c, c/b == a && !(a == -1 && b == math.MinInt) && !(b == -1 && a == math.MinInt)
+}
+
+// Returns a * b mod m, in [0, m), even when a * b would overflow.
+func MulMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	if c, ok := Mul(a, b); ok {
+		return c % m
+	}
+	r := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(b)))
+	return int(r.Mod(r, big.NewInt(int64(m))).Int64())
+}
+
+// Returns a + b mod m, in [0, m).
+func AddMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	if c, ok := Add(a, b); ok {
+		return c % m
+	}
+	return a - (m - b)
+}
+
+// Congruence is x = R (mod M).
+type Congruence struct {
+	R, M int
+}
+
+func (c Congruence) String() string {
+	return fmt.Sprintf("x = %d (mod %d)", c.R, c.M)
+}
+
+// Solves the congruences together, returning the single congruence all
+// their solutions satisfy: R is the smallest non-negative solution and M
+// the lcm of the moduli. The moduli needn't be coprime, but if they share
+// factors the congruences might conflict, giving ErrNoSolution. Working is
+// done in big.Int, so only a result that doesn't fit gives ErrOverflow.
+func CRT(cs ...Congruence) (Congruence, error) {
+	bcs := []BigCongruence{}
+	for _, c := range cs {
+		bcs = append(bcs, BigCongruence{big.NewInt(int64(c.R)), big.NewInt(int64(c.M))})
+	}
+	b, err := BigCRT(bcs...)
+	if err != nil {
+		return Congruence{}, err
+	}
+	if !b.M.IsInt64() || !b.R.IsInt64() || int64(int(b.M.Int64())) != b.M.Int64() {
+		return Congruence{}, fmt.Errorf("crt modulus %s: %w", b.M, ErrOverflow)
+	}
+	return Congruence{int(b.R.Int64()), int(b.M.Int64())}, nil
+}
+
+// BigCongruence is x = R (mod M), for any size of number.
+type BigCongruence struct {
+	R, M *big.Int
+}
+
+func (c BigCongruence) String() string {
+	return fmt.Sprintf("x = %s (mod %s)", c.R, c.M)
+}
+
+// CRT in big.Int, see CRT.
+func BigCRT(cs ...BigCongruence) (BigCongruence, error) {
+	r, m := big.NewInt(0), big.NewInt(1)
+	for _, c := range cs {
+		if c.M.Sign() <= 0 {
+			return BigCongruence{}, fmt.Errorf("bad modulus in %s", c)
+		}
+		// x = r + m*k, and we need r + m*k = c.R (mod c.M), so
+		// m*k = c.R - r (mod c.M), solvable iff g = gcd(m, c.M) divides
+		// c.R - r.
+		g, inv := new(big.Int), new(big.Int)
+		g.GCD(inv, nil, m, c.M)
+		diff := new(big.Int).Sub(c.R, r)
+		if new(big.Int).Mod(diff, g).Sign() != 0 {
+			return BigCongruence{}, fmt.Errorf("%s conflicts with x = %s (mod %s): %w", c, r, m, ErrNoSolution)
+		}
+		mg := new(big.Int).Div(c.M, g)
+		k := diff.Div(diff, g)
+		k.Mul(k, inv).Mod(k, mg)
+		r.Add(r, k.Mul(k, m))
+		m.Mul(m, mg)
+		r.Mod(r, m)
+	}
+	return BigCongruence{r, m}, nil
+}
+
+// Int is an integer kept as an int while it fits, moving to a big.Int
+// when it doesn't. The zero value is 0.
+type Int struct {
+	small int
+	big   *big.Int
+}
+
+func NewInt(v int) Int {
+	return Int{small: v}
+}
+
+// Whether the value has outgrown an int.
+func (a Int) IsBig() bool {
+	return a.big != nil
+}
+
+// The value as a big.Int (always a new one).
+func (a Int) Big() *big.Int {
+	if a.big != nil {
+		return new(big.Int).Set(a.big)
+	}
+	return big.NewInt(int64(a.small))
+}
+
+// The value as an int, and false if it doesn't fit.
+func (a Int) Int() (int, bool) {
+	return a.small, a.big == nil
+}
+
+func (a Int) String() string {
+	if a.big != nil {
+		return a.big.String()
+	}
+	return fmt.Sprint(a.small)
+}
+
+// Returns b as an Int, back to an int if it fits.
+func fromBig(b *big.Int) Int {
+	if b.IsInt64() && int64(int(b.Int64())) == b.Int64() {
+		return Int{small: int(b.Int64())}
+	}
+	return Int{big: b}
+}
+
+func (a Int) Add(b Int) Int {
+	if a.big == nil && b.big == nil {
+		if c, ok := Add(a.small, b.small); ok {
+			return Int{small: c}
+		}
+	}
+	return fromBig(new(big.Int).Add(a.Big(), b.Big()))
+}
+
+func (a Int) Mul(b Int) Int {
+	if a.big == nil && b.big == nil {
+		if c, ok := Mul(a.small, b.small); ok {
+			return Int{small: c}
+		}
+	}
+	return fromBig(new(big.Int).Mul(a.Big(), b.Big()))
+}
+
+// Returns a mod m, in [0, m).
+func (a Int) Mod(m int) int {
+	if a.big == nil {
+		return Mod(a.small, m)
+	}
+	return int(new(big.Int).Mod(a.big, big.NewInt(int64(m))).Int64())
+}
+
+func (a Int) Cmp(b Int) int {
+	if a.big == nil && b.big == nil {
+		switch {
+		case a.small < b.small:
+			return -1
+		case a.small > b.small:
+			return 1
+		}
+		return 0
+	}
+	return a.Big().Cmp(b.Big())
+}
@@ -0,0 +1,170 @@
+package numtheory
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GCD(t *testing.T) {
+	assert.Equal(t, 6, GCD(12, 18))
+	assert.Equal(t, 6, GCD(-12, 18))
+	assert.Equal(t, 5, GCD(0, 5))
+	assert.Equal(t, 0, GCD(0, 0))
+
+	l, err := LCM(4, 6, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 60, l)
+	l, err = LCM()
+	require.NoError(t, err)
+	assert.Equal(t, 1, l)
+
+	// 2023 day 8 sized: fits comfortably.
+	l, err = LCM(20093, 12169, 22357, 14999, 13301, 17263)
+	require.NoError(t, err)
+	assert.Equal(t, 10371555451871, l)
+
+	_, err = LCM(math.MaxInt, math.MaxInt-1)
+	assert.True(t, errors.Is(err, ErrOverflow), err)
+	want, _ := new(big.Int).SetString("85070591730234615838173535747377725442", 10)
+	assert.Equal(t, want, BigLCM(math.MaxInt, math.MaxInt-1, 2))
+}
+
+func Test_ExtGCD(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		a, b := r.Intn(2000)-1000, r.Intn(2000)-1000
+		g, x, y := ExtGCD(a, b)
+		assert.Equal(t, GCD(a, b), g, "%d %d", a, b)
+		assert.Equal(t, g, a*x+b*y, "%d %d", a, b)
+	}
+
+	inv, err := ModInverse(3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 5, inv)
+	inv, err = ModInverse(-3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inv)
+	_, err = ModInverse(4, 6)
+	assert.True(t, errors.Is(err, ErrNoSolution), err)
+}
+
+func Test_Checked(t *testing.T) {
+	_, ok := Add(math.MaxInt, 1)
+	assert.False(t, ok)
+	_, ok = Add(math.MinInt, -1)
+	assert.False(t, ok)
+	v, ok := Add(math.MaxInt, -1)
+	assert.True(t, ok)
+	assert.Equal(t, math.MaxInt-1, v)
+
+	_, ok = Mul(math.MaxInt/2+1, 2)
+	assert.False(t, ok)
+	_, ok = Mul(-1, math.MinInt)
+	assert.False(t, ok)
+	v, ok = Mul(math.MaxInt/2, -2)
+	assert.True(t, ok)
+	assert.Equal(t, -(math.MaxInt - 1), v)
+
+	assert.Equal(t, 2, Mod(-5, 7))
+	m := math.MaxInt - 24 // 2^63-25 is prime
+	assert.Equal(t, 576, MulMod(-24, -24, m))
+	assert.Equal(t, 625, MulMod(m-25, m-25, m))
+	assert.Equal(t, m-2, AddMod(m-1, m-1, m))
+}
+
+func Test_CRT(t *testing.T) {
+	for _, tc := range []struct {
+		cs   []Congruence
+		want Congruence
+	}{
+		{nil, Congruence{0, 1}},
+		{[]Congruence{{2, 3}, {3, 5}, {2, 7}}, Congruence{23, 105}},
+		{[]Congruence{{-1, 3}, {8, 5}}, Congruence{8, 15}},
+		// Shared factors: 4 and 6 agree mod 2.
+		{[]Congruence{{3, 4}, {5, 6}}, Congruence{11, 12}},
+		{[]Congruence{{1, 6}, {1, 10}, {1, 15}}, Congruence{1, 30}},
+		{[]Congruence{{0, 12}, {0, 4}}, Congruence{0, 12}},
+	} {
+		got, err := CRT(tc.cs...)
+		require.NoError(t, err, tc.cs)
+		assert.Equal(t, tc.want, got, tc.cs)
+	}
+
+	_, err := CRT(Congruence{0, 4}, Congruence{1, 6})
+	assert.True(t, errors.Is(err, ErrNoSolution), err)
+	_, err = CRT(Congruence{0, 0})
+	assert.Error(t, err)
+	_, err = CRT(Congruence{1, math.MaxInt}, Congruence{2, math.MaxInt - 1})
+	assert.True(t, errors.Is(err, ErrOverflow), err)
+	b, err := BigCRT(
+		BigCongruence{big.NewInt(1), big.NewInt(math.MaxInt)},
+		BigCongruence{big.NewInt(2), big.NewInt(math.MaxInt - 1)})
+	require.NoError(t, err)
+	assert.Equal(t, BigLCM(math.MaxInt, math.MaxInt-1), b.M)
+	assert.Equal(t, int64(1), new(big.Int).Mod(b.R, big.NewInt(math.MaxInt)).Int64())
+	assert.Equal(t, int64(2), new(big.Int).Mod(b.R, big.NewInt(math.MaxInt-1)).Int64())
+}
+
+// CRT finds the smallest solution a search would, or none when there is
+// none.
+func Test_CRTBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		cs := []Congruence{}
+		l := 1
+		for i := r.Intn(3) + 1; i > 0; i-- {
+			m := r.Intn(12) + 1
+			cs = append(cs, Congruence{r.Intn(30) - 15, m})
+			l, _ = LCM(l, m)
+		}
+		want := -1
+		for x := 0; x < l && want < 0; x++ {
+			want = x
+			for _, c := range cs {
+				if Mod(x-c.R, c.M) != 0 {
+					want = -1
+				}
+			}
+		}
+		got, err := CRT(cs...)
+		if want < 0 {
+			assert.True(t, errors.Is(err, ErrNoSolution), "%v: %v", cs, err)
+			continue
+		}
+		require.NoError(t, err, cs)
+		assert.Equal(t, Congruence{want, l}, got, cs)
+	}
+}
+
+func Test_Int(t *testing.T) {
+	var z Int
+	assert.Equal(t, "0", z.String())
+
+	a := NewInt(math.MaxInt)
+	b := a.Add(NewInt(1))
+	assert.True(t, b.IsBig())
+	assert.Equal(t, "9223372036854775808", b.String())
+	_, ok := b.Int()
+	assert.False(t, ok)
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, -1, a.Cmp(b))
+
+	// Back down to an int again.
+	c := b.Add(NewInt(-2))
+	assert.False(t, c.IsBig())
+	v, ok := c.Int()
+	assert.True(t, ok)
+	assert.Equal(t, math.MaxInt-1, v)
+
+	sq := a.Mul(a)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(math.MaxInt), big.NewInt(math.MaxInt)), sq.Big())
+	assert.Equal(t, 1, sq.Mod(math.MaxInt-1))
+	assert.Equal(t, 0, NewInt(6).Mul(NewInt(7)).Cmp(NewInt(42)))
+	assert.Equal(t, 4, NewInt(-3).Mod(7))
+}
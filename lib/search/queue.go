@@ -0,0 +1,50 @@
+package search
+
+import "container/heap"
+
+// Queue is a min-priority queue, entries with equal priority come out in
+// the order they went in.
+type Queue[T any] struct {
+	h entries[T]
+	n int
+}
+
+type entry[T any] struct {
+	v        T
+	priority int
+	seq      int
+}
+
+func (q *Queue[T]) Len() int {
+	return len(q.h)
+}
+
+func (q *Queue[T]) Push(v T, priority int) {
+	heap.Push(&q.h, entry[T]{v: v, priority: priority, seq: q.n})
+	q.n++
+}
+
+// Removes and returns the entry with the lowest priority.
+func (q *Queue[T]) Pop() (T, int) {
+	e := heap.Pop(&q.h).(entry[T])
+	return e.v, e.priority
+}
+
+// entries implements heap.Interface.
+type entries[T any] []entry[T]
+
+func (h entries[T]) Len() int { return len(h) }
+func (h entries[T]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h entries[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *entries[T]) Push(x any)   { *h = append(*h, x.(entry[T])) }
+func (h *entries[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
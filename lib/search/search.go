@@ -0,0 +1,163 @@
+// Package search finds shortest paths through graphs that are described by
+// a function giving each state's neighbours, rather than built up front.
+//
+// States can be anything comparable: a grid position, a valve, or a
+// position plus the minute it is reached at.
+package search
+
+import "fmt"
+
+type Graph[S comparable] struct {
+	// Returns the states reachable in one step from s.
+	Neighbors func(s S) []S
+	// Cost of the step from -> to, nil means every step costs 1.
+	Cost func(from, to S) int
+	// Estimate of the remaining cost from s to the goal, which must never
+	// be more than the real cost. nil (or 0) makes Search a plain Dijkstra.
+	// If it can also drop by more than a step's cost from one state to the
+	// next, states are reopened when a cheaper path to them turns up, so
+	// the path found is still the cheapest.
+	Heuristic func(s S) int
+}
+
+// Result of a search.
+type Result[S comparable] struct {
+	Found bool
+	// Total cost of the path (steps, for BFS).
+	Distance int
+	// From the start to the goal, both included.
+	Path []S
+	// Number of states taken off the queue.
+	Explored int
+}
+
+func (r Result[S]) String() string {
+	if !r.Found {
+		return fmt.Sprintf("not found (explored %d)", r.Explored)
+	}
+	return fmt.Sprintf("distance %d (explored %d)", r.Distance, r.Explored)
+}
+
+func (g Graph[S]) cost(from, to S) int {
+	if g.Cost == nil {
+		return 1
+	}
+	return g.Cost(from, to)
+}
+
+func (g Graph[S]) heuristic(s S) int {
+	if g.Heuristic == nil {
+		return 0
+	}
+	return g.Heuristic(s)
+}
+
+func path[S comparable](prev map[S]S, end S) (rv []S) {
+	for s, ok := end, true; ok; s, ok = prev[s] {
+		rv = append(rv, s)
+	}
+	for i, j := 0, len(rv)-1; i < j; i, j = i+1, j-1 {
+		rv[i], rv[j] = rv[j], rv[i]
+	}
+	return
+}
+
+// Search finds the cheapest path from any of the starts to a state for which
+// goal returns true, using A* (or Dijkstra without a Heuristic).
+func (g Graph[S]) Search(goal func(S) bool, start ...S) (r Result[S]) {
+	dist := map[S]int{}
+	prev := map[S]S{}
+	done := map[S]bool{}
+	q := Queue[S]{}
+	for _, s := range start {
+		dist[s] = 0
+		q.Push(s, g.heuristic(s))
+	}
+	for q.Len() > 0 {
+		s, _ := q.Pop()
+		if done[s] {
+			continue
+		}
+		done[s] = true
+		r.Explored++
+		if goal(s) {
+			r.Found = true
+			r.Distance = dist[s]
+			r.Path = path(prev, s)
+			return
+		}
+		for _, n := range g.Neighbors(s) {
+			d := dist[s] + g.cost(s, n)
+			if old, seen := dist[n]; seen && old <= d {
+				continue
+			}
+			dist[n] = d
+			done[n] = false
+			prev[n] = s
+			q.Push(n, d+g.heuristic(n))
+		}
+	}
+	return
+}
+
+// BFS finds the path with the fewest steps from any of the starts to a state
+// for which goal returns true. Cost and Heuristic are ignored.
+func (g Graph[S]) BFS(goal func(S) bool, start ...S) (r Result[S]) {
+	dist := map[S]int{}
+	prev := map[S]S{}
+	q := []S{}
+	for _, s := range start {
+		if _, seen := dist[s]; !seen {
+			dist[s] = 0
+			q = append(q, s)
+		}
+	}
+	for len(q) > 0 {
+		s := q[0]
+		q = q[1:]
+		r.Explored++
+		if goal(s) {
+			r.Found = true
+			r.Distance = dist[s]
+			r.Path = path(prev, s)
+			return
+		}
+		for _, n := range g.Neighbors(s) {
+			if _, seen := dist[n]; seen {
+				continue
+			}
+			dist[n] = dist[s] + 1
+			prev[n] = s
+			q = append(q, n)
+		}
+	}
+	return
+}
+
+// Distances returns the cost of the cheapest path from the starts to every
+// reachable state.
+func (g Graph[S]) Distances(start ...S) map[S]int {
+	dist := map[S]int{}
+	done := map[S]bool{}
+	q := Queue[S]{}
+	for _, s := range start {
+		dist[s] = 0
+		q.Push(s, 0)
+	}
+	for q.Len() > 0 {
+		s, d := q.Pop()
+		if done[s] {
+			continue
+		}
+		done[s] = true
+		for _, n := range g.Neighbors(s) {
+			nd := d + g.cost(s, n)
+			if old, seen := dist[n]; seen && old <= nd {
+				continue
+			}
+			dist[n] = nd
+			q.Push(n, nd)
+		}
+	}
+	return dist
+}
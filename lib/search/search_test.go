@@ -0,0 +1,141 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Queue(t *testing.T) {
+	q := Queue[string]{}
+	q.Push("c", 3)
+	q.Push("a", 1)
+	q.Push("b", 2)
+	q.Push("a2", 1)
+	got := []string{}
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"a", "a2", "b", "c"}, got)
+}
+
+type pos struct{ row, col int }
+
+const maze = `S.#.....
+.##.###.
+....#...
+.####.#.
+......#E`
+
+func mazeGraph() (g Graph[pos], start, end pos) {
+	rows := strings.Split(maze, "\n")
+	open := func(p pos) bool {
+		return p.row >= 0 && p.col >= 0 && p.row < len(rows) && p.col < len(rows[0]) && rows[p.row][p.col] != '#'
+	}
+	for r, row := range rows {
+		if c := strings.IndexByte(row, 'S'); c != -1 {
+			start = pos{r, c}
+		}
+		if c := strings.IndexByte(row, 'E'); c != -1 {
+			end = pos{r, c}
+		}
+	}
+	g.Neighbors = func(p pos) (rv []pos) {
+		for _, n := range []pos{{p.row - 1, p.col}, {p.row + 1, p.col}, {p.row, p.col - 1}, {p.row, p.col + 1}} {
+			if open(n) {
+				rv = append(rv, n)
+			}
+		}
+		return
+	}
+	return
+}
+
+func Test_Maze(t *testing.T) {
+	g, start, end := mazeGraph()
+	at := func(p pos) bool { return p == end }
+
+	bfs := g.BFS(at, start)
+	assert.True(t, bfs.Found)
+	assert.Equal(t, 15, bfs.Distance)
+	assert.Len(t, bfs.Path, 16)
+	assert.Equal(t, start, bfs.Path[0])
+	assert.Equal(t, end, bfs.Path[15])
+
+	dijkstra := g.Search(at, start)
+	assert.Equal(t, bfs.Distance, dijkstra.Distance)
+
+	g.Heuristic = func(p pos) int {
+		return max(end.row-p.row, p.row-end.row) + max(end.col-p.col, p.col-end.col)
+	}
+	astar := g.Search(at, start)
+	assert.Equal(t, bfs.Distance, astar.Distance)
+	assert.Less(t, astar.Explored, dijkstra.Explored)
+
+	assert.Equal(t, 15, g.Distances(start)[end])
+
+	// Several starts, nearest wins.
+	r := g.BFS(at, start, pos{2, 7})
+	assert.Equal(t, 2, r.Distance)
+	assert.Equal(t, []pos{{2, 7}, {3, 7}, {4, 7}}, r.Path)
+
+	r = g.Search(func(p pos) bool { return p == pos{9, 9} }, start)
+	assert.False(t, r.Found)
+	assert.Equal(t, "not found (explored 27)", r.String())
+}
+
+func Test_Weighted(t *testing.T) {
+	edges := map[string]map[string]int{
+		"a": {"b": 1, "c": 10},
+		"b": {"d": 1},
+		"d": {"c": 1},
+		"c": {"e": 1},
+	}
+	g := Graph[string]{
+		Neighbors: func(s string) (rv []string) {
+			for n := range edges[s] {
+				rv = append(rv, n)
+			}
+			return
+		},
+		Cost: func(from, to string) int { return edges[from][to] },
+	}
+	at := func(s string) bool { return s == "e" }
+
+	r := g.Search(at, "a")
+	assert.Equal(t, 4, r.Distance)
+	assert.Equal(t, []string{"a", "b", "d", "c", "e"}, r.Path)
+
+	r = g.BFS(at, "a")
+	assert.Equal(t, 2, r.Distance)
+	assert.Equal(t, []string{"a", "c", "e"}, r.Path)
+
+	assert.Equal(t, map[string]int{"a": 0, "b": 1, "c": 3, "d": 2, "e": 4}, g.Distances("a"))
+}
+
+// An admissible but inconsistent heuristic makes c look cheap via b before
+// the cheaper path via a is explored, so c has to be reopened.
+func Test_Inconsistent(t *testing.T) {
+	edges := map[string]map[string]int{
+		"s": {"a": 1, "b": 1},
+		"a": {"c": 1},
+		"b": {"c": 2},
+		"c": {"g": 3},
+	}
+	g := Graph[string]{
+		Neighbors: func(s string) (rv []string) {
+			for n := range edges[s] {
+				rv = append(rv, n)
+			}
+			return
+		},
+		Cost:      func(from, to string) int { return edges[from][to] },
+		Heuristic: func(s string) int { return map[string]int{"a": 4}[s] },
+	}
+	r := g.Search(func(s string) bool { return s == "g" }, "s")
+	assert.True(t, r.Found)
+	assert.Equal(t, 5, r.Distance)
+	assert.Equal(t, []string{"s", "a", "c", "g"}, r.Path)
+}
@@ -0,0 +1,105 @@
+package solver
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// Program is a Solver for the older standalone solutions: a `package main`
+// file that reads its input from stdin and prints the answer somewhere in
+// its output. It is run with `go run` from its own directory.
+type Program struct {
+	// Path to the .go file.
+	File string
+	// Extra command line arguments the program expects.
+	Args []string
+	// Used instead of Args when the input file name starts with "sample",
+	// for programs whose parameters differ between sample and real input.
+	SampleArgs []string
+	// The whole of stdout is the answer (e.g. a picture of letters), rather
+	// than a value picked out of the last line.
+	Raw bool
+}
+
+func (p Program) String() string {
+	return filepath.Base(p.File)
+}
+
+func (p Program) args(filename string) []string {
+	if p.SampleArgs != nil && strings.HasPrefix(filepath.Base(filename), "sample") {
+		return p.SampleArgs
+	}
+	return p.Args
+}
+
+func (p Program) Solve(filename string) (string, error) {
+	in, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", append([]string{"run", filepath.Base(p.File)}, p.args(filename)...)...)
+	cmd.Dir = filepath.Dir(p.File)
+	cmd.Stdin = in
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	glog.V(1).Infof("running %v in %s < %s", cmd.Args, cmd.Dir, filename)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w: %s", p, err, lastLine(stderr.String()))
+	}
+
+	if p.Raw {
+		return strings.TrimSpace(stdout.String()), nil
+	}
+	line := lastLine(stdout.String())
+	if line == "" {
+		// Some programs only report via the log package.
+		line = logPrefix.ReplaceAllString(lastLine(stderr.String()), "")
+	}
+	if line == "" {
+		return "", fmt.Errorf("%s: no output", p)
+	}
+	return Answer(line), nil
+}
+
+var logPrefix = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} `)
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// Answer picks the answer out of a line of program output. A line with a
+// single value is returned as is, otherwise the last number on the line is
+// used (so "Move to 2 using 37 fuel" gives 37). Whole floats lose their
+// decimals.
+func Answer(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 1 {
+		return fields[0]
+	}
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := strings.TrimRight(fields[i], ".,!")
+		if _, err := strconv.ParseInt(f, 10, 64); err == nil {
+			return f
+		}
+		if v, err := strconv.ParseFloat(f, 64); err == nil {
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				return strconv.FormatInt(int64(v), 10)
+			}
+			return f
+		}
+	}
+	return line
+}
@@ -0,0 +1,118 @@
+// Package solver gives every puzzle solution, whichever year it is from and
+// however it was originally run, a common way to be called: by year, day and
+// part with the name of an input file.
+//
+// Solutions register themselves (usually from an init func) with Register,
+// and callers find them again with Lookup or Keys.
+package solver
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Solver produces the answer for one part of a puzzle from an input file.
+type Solver interface {
+	Solve(filename string) (string, error)
+}
+
+// Func adapts an ordinary function to a Solver, the answer is formatted with
+// fmt.Sprint so ints, strings, *big.Int etc can all be returned directly.
+type Func func(filename string) (any, error)
+
+func (f Func) Solve(filename string) (string, error) {
+	v, err := f(filename)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(v), nil
+}
+
+// Key identifies a single part of a puzzle.
+type Key struct {
+	Year, Day, Part int
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%d/%d part %d", k.Year, k.Day, k.Part)
+}
+
+func (k Key) compare(o Key) int {
+	if k.Year != o.Year {
+		return k.Year - o.Year
+	}
+	if k.Day != o.Day {
+		return k.Day - o.Day
+	}
+	return k.Part - o.Part
+}
+
+// Renderer is a Solver that can also draw what it found, e.g. the path it
+// took, to the file out ("-" for stdout).
+type Renderer interface {
+	Solver
+	Render(filename, out string) error
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[Key]Solver{}
+)
+
+// Register makes s the solver for the given part. It panics if the part
+// already has a solver, or the key is obviously not a puzzle.
+func Register(year, day, part int, s Solver) {
+	k := Key{Year: year, Day: day, Part: part}
+	if day < 1 || day > 25 || part < 1 || part > 2 {
+		panic(fmt.Sprintf("solver: bad key %s", k))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, found := registry[k]; found {
+		panic(fmt.Sprintf("solver: %s registered twice", k))
+	}
+	registry[k] = s
+}
+
+// Lookup returns the solver for the given part.
+func Lookup(year, day, part int) (Solver, error) {
+	k := Key{Year: year, Day: day, Part: part}
+	mu.RLock()
+	defer mu.RUnlock()
+	s, found := registry[k]
+	if !found {
+		return nil, fmt.Errorf("no solver for %s", k)
+	}
+	return s, nil
+}
+
+// Solve looks up and runs the solver for the given part.
+func Solve(year, day, part int, filename string) (string, error) {
+	s, err := Lookup(year, day, part)
+	if err != nil {
+		return "", err
+	}
+	return s.Solve(filename)
+}
+
+// Keys returns every registered part, ordered by year, day then part.
+func Keys() (rv []Key) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for k := range registry {
+		rv = append(rv, k)
+	}
+	slices.SortFunc(rv, Key.compare)
+	return
+}
+
+// Year returns the registered parts for a single year, in order.
+func Year(year int) (rv []Key) {
+	for _, k := range Keys() {
+		if k.Year == year {
+			rv = append(rv, k)
+		}
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package solver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry(t *testing.T) {
+	Register(2001, 2, 1, Func(func(string) (any, error) { return 42, nil }))
+	Register(2001, 1, 2, Func(func(f string) (any, error) { return f, nil }))
+	Register(2000, 25, 1, Func(func(string) (any, error) { return "x", nil }))
+
+	assert.Panics(t, func() { Register(2001, 2, 1, Func(nil)) })
+	assert.Panics(t, func() { Register(2001, 26, 1, Func(nil)) })
+	assert.Panics(t, func() { Register(2001, 1, 3, Func(nil)) })
+
+	assert.Equal(t, []Key{{2000, 25, 1}, {2001, 1, 2}, {2001, 2, 1}}, Keys())
+	assert.Equal(t, []Key{{2001, 1, 2}, {2001, 2, 1}}, Year(2001))
+
+	a, err := Solve(2001, 2, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "42", a)
+	a, err = Solve(2001, 1, 2, "sample")
+	require.NoError(t, err)
+	assert.Equal(t, "sample", a)
+
+	_, err = Lookup(2001, 2, 2)
+	assert.ErrorContains(t, err, "2001/2 part 2")
+}
+
+func Test_Answer(t *testing.T) {
+	for line, want := range map[string]string{
+		"CMZ":                           "CMZ",
+		"Move to 2 using 37 fuel":       "37",
+		"Power consumption: 198.000000": "198",
+		"There are 5 points with >2 lines passing through": "5",
+		"Password: 6 * 1000 + 8 * 4 + 0 = 6032":            "6032",
+		"301  looks like the right answer!":                "301",
+		"no numbers here":                                  "no numbers here",
+	} {
+		assert.Equal(t, want, Answer(line), line)
+	}
+}
+
+const program = `package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	n := 0
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		n++
+	}
+	if len(os.Args) > 1 {
+		log.Printf("%s lines: %d", os.Args[1], n)
+		return
+	}
+	fmt.Println("debug")
+	fmt.Println("Lines:", n)
+}
+`
+
+func Test_Program(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "lines.go")
+	require.NoError(t, os.WriteFile(file, []byte(program), 0644))
+	input := filepath.Join(dir, "input")
+	require.NoError(t, os.WriteFile(input, []byte(strings.Repeat("x\n", 3)), 0644))
+	sample := filepath.Join(dir, "sample")
+	require.NoError(t, os.WriteFile(sample, []byte("x\n"), 0644))
+
+	a, err := Program{File: file}.Solve(input)
+	require.NoError(t, err)
+	assert.Equal(t, "3", a)
+
+	// Via stderr, with the log prefix stripped.
+	p := Program{File: file, Args: []string{"all"}, SampleArgs: []string{"few"}}
+	a, err = p.Solve(input)
+	require.NoError(t, err)
+	assert.Equal(t, "3", a)
+	a, err = p.Solve(sample)
+	require.NoError(t, err)
+	assert.Equal(t, "1", a)
+
+	a, err = Program{File: file, Raw: true}.Solve(sample)
+	require.NoError(t, err)
+	assert.Equal(t, "debug\nLines: 1", a)
+
+	_, err = Program{File: file}.Solve(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,248 @@
+// aoc runs any registered puzzle solution against an input file.
+//
+//	aoc run 2023 18 -part 2 -input sample
+//	aoc run 2023 16 -part 2 -render best.png
+//	aoc run 2022            # every day and part of 2022
+//	aoc list 2021
+//	aoc record 2023 18 1 too high 100724
+//
+// Input names without a directory are looked up in the day's own directory,
+// so -input sample means 2023/18/sample for the example above.
+//
+// Answers for the real input are checked against the day's answers ledger,
+// and record adds to it once the site has said whether a guess was right.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattbnz/aoc/lib/answers"
+	"github.com/mattbnz/aoc/lib/solver"
+	"github.com/mattbnz/aoc/solutions"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Usage: aoc [flags] <command> ...
+
+Commands:
+  run <year> [day] [-part N] [-input FILE] [-render FILE]
+  list [year]
+  record <year> <day> <part> <answer|too high|too low|wrong> <value>
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch flag.Arg(0) {
+	case "run":
+		err = run(os.Stdout, flag.Args()[1:])
+	case "list":
+		err = list(os.Stdout, flag.Args()[1:])
+	case "record":
+		err = record(os.Stdout, flag.Args()[1:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Splits the leading year/day arguments from the flags that follow them.
+func positional(args []string, max int) (nums []int, rest []string, err error) {
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if len(nums) == max {
+			return nil, nil, fmt.Errorf("unexpected argument %q", args[0])
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("bad year or day %q", args[0])
+		}
+		nums = append(nums, n)
+		args = args[1:]
+	}
+	return nums, args, nil
+}
+
+// Parses fs's flags and up to max year/day arguments, which can come
+// before, after or between the flags.
+func parseArgs(fs *flag.FlagSet, args []string, max int) (nums []int, err error) {
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return nums, nil
+		}
+		if len(nums) == max {
+			return nil, fmt.Errorf("unexpected argument %q", args[0])
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("bad year or day %q", args[0])
+		}
+		nums = append(nums, n)
+		args = args[1:]
+	}
+}
+
+func run(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	part := fs.Int("part", 0, "part to run (default both)")
+	input := fs.String("input", "input", "input file, relative to the day's directory unless it contains a /")
+	render := fs.String("render", "", "also draw what the solver found to this file (- for stdout), for a single part that can")
+	nums, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	if len(nums) == 0 {
+		return errors.New("run needs a year")
+	}
+
+	keys := []solver.Key{}
+	for _, k := range solver.Year(nums[0]) {
+		if len(nums) > 1 && k.Day != nums[1] {
+			continue
+		}
+		if *part != 0 && k.Part != *part {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no solvers for %v", nums)
+	}
+	if *render != "" && len(keys) != 1 {
+		return fmt.Errorf("-render needs a single part, not %d", len(keys))
+	}
+
+	failed := 0
+	var total time.Duration
+	for _, k := range keys {
+		filename := *input
+		if !strings.Contains(filename, "/") {
+			filename = filepath.Join(solutions.Dir(k.Year, k.Day), filename)
+		}
+		s, err := solver.Lookup(k.Year, k.Day, k.Part)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		answer, err := s.Solve(filename)
+		took := time.Since(start)
+		total += took
+		if err != nil {
+			fmt.Fprintf(w, "%s: error: %v\n", k, err)
+			failed++
+			continue
+		}
+		note, err := check(k, filename, answer)
+		if err != nil {
+			failed++
+		}
+		if strings.Contains(answer, "\n") {
+			answer = "\n" + answer
+		}
+		fmt.Fprintf(w, "%s: %s (%s)%s\n", k, answer, took.Round(time.Microsecond), note)
+		if *render != "" {
+			r, ok := s.(solver.Renderer)
+			if !ok {
+				return fmt.Errorf("%s can't render", k)
+			}
+			if err := r.Render(filename, *render); err != nil {
+				return fmt.Errorf("%s: render: %w", k, err)
+			}
+		}
+	}
+	if len(keys) > 1 {
+		fmt.Fprintf(w, "%d parts in %s\n", len(keys), total.Round(time.Millisecond))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d parts failed", failed, len(keys))
+	}
+	return nil
+}
+
+// Checks answers for the real input against the day's ledger, returning a
+// note to print after the answer.
+func check(k solver.Key, filename, answer string) (string, error) {
+	if filepath.Base(filename) != "input" {
+		return "", nil
+	}
+	l, err := answers.NewLedger(filepath.Join(filepath.Dir(filename), answers.Filename))
+	if err != nil {
+		return fmt.Sprintf(" [ledger: %v]", err), err
+	}
+	confirmed, err := l.Check(k.Part, answer)
+	if err != nil {
+		return fmt.Sprintf(" [WRONG: %v]", err), err
+	} else if confirmed {
+		return " [confirmed]", nil
+	}
+	return "", nil
+}
+
+func record(w io.Writer, args []string) error {
+	if len(args) < 4 {
+		return errors.New("record needs a year, day, part, verdict and value")
+	}
+	nums, _, err := positional(args[:3], 3)
+	if err != nil {
+		return err
+	}
+	e := answers.Entry{Part: nums[2], Value: args[len(args)-1]}
+	e.Verdict, err = answers.NewVerdict(strings.Join(args[3:len(args)-1], " "))
+	if err != nil {
+		return err
+	}
+	if _, err := answers.NewEntry(e.String()); err != nil {
+		return err
+	}
+	filename := filepath.Join(solutions.Dir(nums[0], nums[1]), answers.Filename)
+	if err := answers.Record(filename, e); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: recorded %s\n", filename, e)
+	return nil
+}
+
+func list(w io.Writer, args []string) error {
+	nums, _, err := positional(args, 1)
+	if err != nil {
+		return err
+	}
+	keys := solver.Keys()
+	if len(nums) > 0 {
+		keys = solver.Year(nums[0])
+	}
+	for _, k := range keys {
+		s, _ := solver.Lookup(k.Year, k.Day, k.Part)
+		if p, ok := s.(solver.Program); ok {
+			fmt.Fprintf(w, "%s\t%s\n", k, p)
+		} else {
+			fmt.Fprintf(w, "%s\n", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattbnz/aoc/lib/solver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run(&out, []string{"2023", "18", "-part", "2", "-input", "sample"}))
+	assert.Regexp(t, `^2023/18 part 2: 952408144115 \(.*\)\n$`, out.String())
+
+	for _, args := range [][]string{
+		{"-part", "2", "-input", "sample", "2023", "18"},
+		{"2023", "-part", "2", "18", "-input", "sample"},
+	} {
+		out.Reset()
+		require.NoError(t, run(&out, args), args)
+		assert.Regexp(t, `^2023/18 part 2: 952408144115 \(.*\)\n$`, out.String(), args)
+	}
+
+	out.Reset()
+	require.NoError(t, run(&out, []string{"2023", "9", "-input", "sample"}))
+	assert.Regexp(t, `^2023/9 part 1: 114 .*\n2023/9 part 2: 2 .*\n2 parts in .*\n$`, out.String())
+
+	out.Reset()
+	assert.Error(t, run(&out, []string{"2023", "9", "-input", "missing"}))
+	assert.Contains(t, out.String(), "2023/9 part 1: error: open ")
+
+	rendered := filepath.Join(t.TempDir(), "best.txt")
+	out.Reset()
+	require.NoError(t, run(&out, []string{"2023", "16", "-part", "2", "-input", "sample", "-render", rendered}))
+	text, err := os.ReadFile(rendered)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "51 tiles energized")
+	assert.Error(t, run(&out, []string{"2023", "16", "-input", "sample", "-render", rendered}))
+	assert.Error(t, run(&out, []string{"2023", "9", "-part", "1", "-input", "sample", "-render", rendered}))
+
+	assert.Error(t, run(&out, []string{}))
+	assert.Error(t, run(&out, []string{"2023", "x"}))
+	assert.Error(t, run(&out, []string{"2023", "1", "2"}))
+	assert.Error(t, run(&out, []string{"2023", "17"}))
+}
+
+func Test_List(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, list(&out, []string{"2022"}))
+	assert.Contains(t, out.String(), "2022/16 part 2\tpressure3.go\n")
+}
+
+func Test_Check(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "answers"), []byte("1 too high 100\n2 answer 7\n"), 0644))
+	k := solver.Key{Year: 2023, Day: 1, Part: 1}
+
+	note, err := check(k, filepath.Join(dir, "input"), "150")
+	assert.Error(t, err)
+	assert.Equal(t, " [WRONG: 150 is too high, 100 already was]", note)
+	note, err = check(k, filepath.Join(dir, "sample"), "150")
+	assert.NoError(t, err)
+	assert.Empty(t, note)
+
+	k.Part = 2
+	note, err = check(k, filepath.Join(dir, "input"), "7")
+	assert.NoError(t, err)
+	assert.Equal(t, " [confirmed]", note)
+}
@@ -0,0 +1,48 @@
+module github.com/mattbnz/aoc/solutions
+
+go 1.21.1
+
+require (
+	day10 v0.0.0-00010101000000-000000000000
+	day11 v0.0.0-00010101000000-000000000000
+	day12 v0.0.0-00010101000000-000000000000
+	day13 v0.0.0-00010101000000-000000000000
+	day14 v0.0.0-00010101000000-000000000000
+	day15 v0.0.0-00010101000000-000000000000
+	day16 v0.0.0-00010101000000-000000000000
+	day18 v0.0.0-00010101000000-000000000000
+	day19 v0.0.0-00010101000000-000000000000
+	day3 v0.0.0-00010101000000-000000000000
+	day5 v0.0.0-00010101000000-000000000000
+	day7 v0.0.0-00010101000000-000000000000
+	day8 v0.0.0-00010101000000-000000000000
+	day9 v0.0.0-00010101000000-000000000000
+	github.com/mattbnz/aoc/lib v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/glog v1.2.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	day10 => ../2023/10
+	day11 => ../2023/11
+	day12 => ../2023/12
+	day13 => ../2023/13
+	day14 => ../2023/14
+	day15 => ../2023/15
+	day16 => ../2023/16
+	day18 => ../2023/18
+	day19 => ../2023/19
+	day3 => ../2023/3
+	day5 => ../2023/5
+	day7 => ../2023/7
+	day8 => ../2023/8
+	day9 => ../2023/9
+	github.com/mattbnz/aoc/lib => ../lib
+)
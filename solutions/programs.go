@@ -0,0 +1,171 @@
+// Package solutions registers every puzzle solution in this repository with
+// the solver package, so that any of them can be run by year, day and part.
+//
+// The 2023 days are packages that register themselves, the older years are
+// standalone programs that are run with `go run` and fed the input on stdin.
+package solutions
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/mattbnz/aoc/lib/solver"
+)
+
+// Root is the top of the repository, where the year directories live.
+var Root = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(filepath.Dir(file))
+}()
+
+type program struct {
+	year, day, part int
+	file            string
+	args            []string
+	sampleArgs      []string
+	raw             bool
+}
+
+// The current solution for each part. Where a day has several attempts
+// (e.g. pressure.go to pressure4.go) only the one giving the right answer is
+// listed, the others are in superseded.
+var programs = []program{
+	{year: 2020, day: 1, part: 1, file: "expenses.go"},
+	{year: 2020, day: 1, part: 2, file: "expenses2.go"},
+	{year: 2020, day: 2, part: 1, file: "password.go"},
+	{year: 2020, day: 2, part: 2, file: "password2.go"},
+	{year: 2020, day: 3, part: 1, file: "trees.go"},
+	{year: 2020, day: 3, part: 2, file: "trees2.go"},
+	{year: 2020, day: 4, part: 1, file: "passport.go"},
+	{year: 2020, day: 4, part: 2, file: "passport2.go"},
+	{year: 2020, day: 5, part: 1, file: "boarding.go"},
+	{year: 2020, day: 5, part: 2, file: "missing.go"},
+	{year: 2020, day: 6, part: 1, file: "customs.go"},
+	{year: 2020, day: 6, part: 2, file: "customs2.go"},
+	{year: 2020, day: 7, part: 1, file: "bags.go"},
+	{year: 2020, day: 7, part: 2, file: "bags2.go"},
+	{year: 2020, day: 8, part: 1, file: "console.go"},
+	{year: 2020, day: 8, part: 2, file: "console2.go"},
+	{year: 2020, day: 9, part: 1, file: "xmas.go"},
+	{year: 2020, day: 9, part: 2, file: "xmas2.go"},
+	{year: 2020, day: 10, part: 1, file: "jolts.go"},
+	{year: 2020, day: 10, part: 2, file: "jolts2.go"},
+	{year: 2020, day: 11, part: 1, file: "seats.go"},
+	{year: 2020, day: 11, part: 2, file: "seats2.go"},
+	{year: 2020, day: 12, part: 1, file: "nav.go"},
+	{year: 2020, day: 12, part: 2, file: "nav2.go"},
+	{year: 2020, day: 13, part: 1, file: "bus.go"},
+	{year: 2020, day: 13, part: 2, file: "bus2.go"},
+	{year: 2020, day: 14, part: 1, file: "dock.go"},
+	{year: 2020, day: 14, part: 2, file: "dock2.go"},
+	{year: 2020, day: 15, part: 1, file: "memory.go"},
+	{year: 2020, day: 15, part: 2, file: "memory2.go"},
+	{year: 2020, day: 16, part: 1, file: "ticket.go"},
+	{year: 2020, day: 16, part: 2, file: "ticket2.go"},
+	{year: 2020, day: 17, part: 1, file: "cubes.go"},
+	{year: 2020, day: 17, part: 2, file: "cubes2.go"},
+	{year: 2020, day: 18, part: 1, file: "math.go"},
+	{year: 2020, day: 18, part: 2, file: "math2.go"},
+	{year: 2020, day: 19, part: 1, file: "messages.go"},
+	{year: 2020, day: 19, part: 2, file: "messages2.go"},
+
+	{year: 2021, day: 1, part: 1, file: "sonar.go"},
+	{year: 2021, day: 1, part: 2, file: "sonar-window.go"},
+	{year: 2021, day: 2, part: 1, file: "navigation.go"},
+	{year: 2021, day: 2, part: 2, file: "navigation2.go"},
+	{year: 2021, day: 3, part: 1, file: "diag.go"},
+	{year: 2021, day: 3, part: 2, file: "diag2.go"},
+	{year: 2021, day: 4, part: 1, file: "bingo.go"},
+	{year: 2021, day: 4, part: 2, file: "bingo2.go"},
+	{year: 2021, day: 5, part: 1, file: "vents.go"},
+	{year: 2021, day: 5, part: 2, file: "diag-vents.go"},
+	{year: 2021, day: 6, part: 1, file: "fish.go"},
+	{year: 2021, day: 6, part: 2, file: "fish2.go"},
+	{year: 2021, day: 7, part: 1, file: "fuel.go"},
+	{year: 2021, day: 7, part: 2, file: "fuel2.go"},
+
+	{year: 2022, day: 1, part: 1, file: "calories.go"},
+	{year: 2022, day: 1, part: 2, file: "calories2.go"},
+	{year: 2022, day: 2, part: 1, file: "rps.go"},
+	{year: 2022, day: 2, part: 2, file: "rps2.go"},
+	{year: 2022, day: 3, part: 1, file: "sack.go"},
+	{year: 2022, day: 3, part: 2, file: "sack2.go"},
+	{year: 2022, day: 4, part: 1, file: "pairs.go"},
+	{year: 2022, day: 4, part: 2, file: "pairs2.go"},
+	{year: 2022, day: 5, part: 1, file: "crates.go"},
+	{year: 2022, day: 5, part: 2, file: "crates2.go"},
+	{year: 2022, day: 6, part: 1, file: "signal.go"},
+	{year: 2022, day: 6, part: 2, file: "signal2.go"},
+	{year: 2022, day: 7, part: 1, file: "fssize.go"},
+	{year: 2022, day: 7, part: 2, file: "fssize2.go"},
+	{year: 2022, day: 8, part: 1, file: "trees.go"},
+	{year: 2022, day: 8, part: 2, file: "trees2.go"},
+	{year: 2022, day: 9, part: 1, file: "bridge.go"},
+	{year: 2022, day: 9, part: 2, file: "bridge2.go"},
+	{year: 2022, day: 10, part: 1, file: "crt.go"},
+	{year: 2022, day: 10, part: 2, file: "crt2.go", raw: true},
+	{year: 2022, day: 11, part: 1, file: "monkeyb.go"},
+	{year: 2022, day: 11, part: 2, file: "monkeyb3.go"},
+	{year: 2022, day: 12, part: 1, file: "hillclimb.go"},
+	{year: 2022, day: 12, part: 2, file: "hillclimb2.go"},
+	{year: 2022, day: 13, part: 1, file: "signal.go"},
+	{year: 2022, day: 13, part: 2, file: "signal2.go"},
+	{year: 2022, day: 14, part: 1, file: "sand.go"},
+	{year: 2022, day: 14, part: 2, file: "sand2.go"},
+	{year: 2022, day: 15, part: 1, file: "beacon.go", args: []string{"2000000"}, sampleArgs: []string{"10"}},
+	{year: 2022, day: 15, part: 2, file: "beacon2.go", args: []string{"4000000"}, sampleArgs: []string{"20"}},
+	{year: 2022, day: 16, part: 1, file: "pressure.go"},
+	{year: 2022, day: 16, part: 2, file: "pressure3.go"},
+	{year: 2022, day: 17, part: 1, file: "rocks.go"},
+	{year: 2022, day: 17, part: 2, file: "rocks3.go"},
+	{year: 2022, day: 18, part: 1, file: "lavacube.go"},
+	{year: 2022, day: 18, part: 2, file: "lavacube3.go"},
+	{year: 2022, day: 19, part: 1, file: "robots.go"},
+	{year: 2022, day: 20, part: 1, file: "gps.go"},
+	{year: 2022, day: 21, part: 1, file: "monkeymath.go"},
+	{year: 2022, day: 21, part: 2, file: "monkeymath2.go"},
+	{year: 2022, day: 22, part: 1, file: "monkeymap.go"},
+	{year: 2022, day: 22, part: 2, file: "monkeymap2.go"},
+	{year: 2022, day: 24, part: 1, file: "blizzard.go"},
+
+	{year: 2023, day: 1, part: 1, file: "calibration.go"},
+	{year: 2023, day: 1, part: 2, file: "calibration2.go"},
+	{year: 2023, day: 2, part: 1, file: "cube-conundrum.go"},
+	{year: 2023, day: 2, part: 2, file: "cube-powers.go"},
+	{year: 2023, day: 4, part: 1, file: "scratchcards.go"},
+	{year: 2023, day: 4, part: 2, file: "scratchcards2.go"},
+	{year: 2023, day: 6, part: 1, file: "boats.go"},
+	{year: 2023, day: 6, part: 2, file: "boats2.go"},
+}
+
+// Earlier attempts that gave a wrong answer, or were replaced by a faster
+// version.
+var superseded = []string{
+	"2022/11/monkeyb2.go",
+	"2022/16/pressure2.go",
+	"2022/16/pressure4.go",
+	"2022/17/rocks2.go",
+	"2022/18/lavacube2.go",
+	"2022/21/monkeybig.go",
+}
+
+// Dir returns the directory holding the given day (and its inputs).
+func Dir(year, day int) string {
+	return filepath.Join(Root, strconv.Itoa(year), strconv.Itoa(day))
+}
+
+func (p program) Program() solver.Program {
+	return solver.Program{
+		File:       filepath.Join(Dir(p.year, p.day), p.file),
+		Args:       p.args,
+		SampleArgs: p.sampleArgs,
+		Raw:        p.raw,
+	}
+}
+
+func init() {
+	for _, p := range programs {
+		solver.Register(p.year, p.day, p.part, p.Program())
+	}
+}
@@ -0,0 +1,89 @@
+package solutions
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mattbnz/aoc/lib/solver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Every standalone program must be registered (or known to be superseded).
+func Test_AllPrograms(t *testing.T) {
+	registered := map[string]bool{}
+	for _, p := range programs {
+		f := p.Program().File
+		_, err := os.Stat(f)
+		assert.NoError(t, err)
+		registered[f] = true
+	}
+
+	n := 0
+	err := filepath.WalkDir(Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (path == filepath.Join(Root, "lib") || path == filepath.Join(Root, "solutions")) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(src, []byte("package main")) && !bytes.Contains(src, []byte("\npackage main")) {
+			return nil
+		}
+		n++
+		rel, _ := filepath.Rel(Root, path)
+		assert.True(t, registered[path] || slices.Contains(superseded, rel), "%s is not registered", rel)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, len(programs)+len(superseded), n)
+}
+
+func Test_Keys(t *testing.T) {
+	keys := solver.Keys()
+	for _, day := range []int{3, 5, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 18, 19} {
+		assert.Contains(t, keys, solver.Key{Year: 2023, Day: day, Part: 1})
+		assert.Contains(t, keys, solver.Key{Year: 2023, Day: day, Part: 2})
+	}
+	assert.Len(t, solver.Year(2021), 14)
+}
+
+func Test_Sample(t *testing.T) {
+	for _, tc := range []struct {
+		year, day, part int
+		input, want     string
+	}{
+		{2020, 13, 2, "sample", "1068781"},
+		{2020, 18, 2, "sample", "282"},
+		{2020, 19, 2, "sample2", "12"},
+		{2021, 7, 2, "sample", "168"},
+		{2022, 11, 2, "sample", "2713310158"},
+		{2022, 15, 2, "sample", "56000011"},
+		{2022, 16, 2, "sample", "1707"},
+		{2022, 17, 2, "sample", "1514285714288"},
+		{2022, 21, 2, "sample", "301"},
+		{2022, 22, 2, "sample", "5031"},
+		{2023, 4, 2, "sample", "30"},
+		{2023, 3, 1, "sample", "4361"},
+		{2023, 11, 1, "sample", "374"},
+		{2023, 12, 2, "sample", "525152"},
+		{2023, 15, 2, "sample", "145"},
+		{2023, 18, 2, "sample", "952408144115"},
+		{2023, 19, 2, "sample", "167409079868000"},
+	} {
+		a, err := solver.Solve(tc.year, tc.day, tc.part, filepath.Join(Dir(tc.year, tc.day), tc.input))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, a, "%d/%d part %d", tc.year, tc.day, tc.part)
+	}
+}
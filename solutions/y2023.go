@@ -0,0 +1,20 @@
+package solutions
+
+// The 2023 days (other than the early standalone programs) register their
+// own solvers.
+import (
+	_ "day10"
+	_ "day11"
+	_ "day12"
+	_ "day13"
+	_ "day14"
+	_ "day15"
+	_ "day16"
+	_ "day18"
+	_ "day19"
+	_ "day3"
+	_ "day5"
+	_ "day7"
+	_ "day8"
+	_ "day9"
+)